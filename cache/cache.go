@@ -0,0 +1,168 @@
+// Пакет cache реализует кеширование ответов VK API для executor'а.
+// Ответы хранятся в хранилище Store в виде HTTP ответов и при попадании в кеш
+// проходят через те же хуки и парсеры, что и ответы сервера
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Время жизни ответов методов по умолчанию
+func DefaultTTL() map[string]time.Duration {
+	return map[string]time.Duration{
+		"database.getCountries":     24 * time.Hour,
+		"database.getCountriesById": 24 * time.Hour,
+		"database.getCities":        24 * time.Hour,
+		"database.getCitiesById":    24 * time.Hour,
+		"database.getRegions":       24 * time.Hour,
+		"groups.getById":            10 * time.Minute,
+		"utils.resolveScreenName":   time.Hour,
+	}
+}
+
+// Заголовок, которым помечаются ответы, полученные из кеша
+const HitHeader = "X-Vkapiexecutor-Cache"
+
+// Сохраненный ответ
+type Entry struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+}
+
+// Сообщает, истек ли срок хранения ответа
+func (e *Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// Хранилище сохраненных ответов.
+// Ключ - отпечаток запроса request.Request.Fingerprint()
+type Store interface {
+	// Возвращает сохраненный ответ. Если ответа нет или срок его хранения истек, возвращает nil
+	Get(ctx context.Context, key string) (*Entry, error)
+	// Сохраняет ответ
+	Set(ctx context.Context, key string, entry *Entry) error
+	// Удаляет ответ
+	Delete(ctx context.Context, key string) error
+}
+
+// Кеш ответов VK API. Реализует executor.ResponseCache
+//
+//	exec.Cache = cache.New(cache.NewMemoryStore(10000))
+type Cache struct {
+	// Хранилище ответов
+	Store Store
+	// Время жизни ответов по методам. Ответы методов, которых нет в списке, не кешируются
+	TTL map[string]time.Duration
+	// Учитывать ли токен в ключе кеша. Если false, ответ, полученный с одним токеном, вернется и для других
+	IncludeToken bool
+	// Вызывается при ошибках хранилища. Ошибки хранилища не прерывают выполнение запроса
+	OnError func(err error)
+}
+
+// Создает кеш с временем жизни ответов по умолчанию
+func New(store Store) *Cache {
+	return &Cache{
+		Store:        store,
+		TTL:          DefaultTTL(),
+		IncludeToken: true,
+	}
+}
+
+// Ключ отключения чтения из кеша в контексте
+type bypassContextKey struct{}
+
+// Отключает чтение ответа из кеша для запроса. Свежий ответ сервера при этом сохраняется в кеш
+//
+//	res, err := exec.DoRequestCtx(cache.WithBypass(ctx), req)
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassContextKey{}, true)
+}
+
+// Сообщает, отключено ли чтение из кеша в контексте
+func IsBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassContextKey{}).(bool)
+	return bypass
+}
+
+// Возвращает ключ кеша для запроса
+func (v *Cache) Key(req *request.Request) string {
+	return req.Fingerprint(v.IncludeToken)
+}
+
+// Сообщает, кешируются ли ответы на запрос
+func (v *Cache) Cacheable(req *request.Request) bool {
+	ttl, ok := v.TTL[req.GetMethod()]
+	return ok && ttl > 0
+}
+
+// Возвращает сохраненный ответ на запрос в виде HTTP ответа без поля Request
+func (v *Cache) Load(ctx context.Context, req *request.Request) (*http.Response, bool) {
+	if IsBypassed(ctx) {
+		return nil, false
+	}
+
+	entry, err := v.Store.Get(ctx, v.Key(req))
+	if err != nil {
+		v.error(err)
+		return nil, false
+	}
+
+	if entry == nil || entry.Expired(time.Now()) {
+		return nil, false
+	}
+
+	header := entry.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(HitHeader, "hit")
+
+	return &http.Response{
+		Status:        strconv.Itoa(entry.StatusCode) + " " + http.StatusText(entry.StatusCode),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+	}, true
+}
+
+// Сохраняет тело успешного ответа на запрос
+func (v *Cache) Save(ctx context.Context, req *request.Request, res *http.Response, body []byte) {
+	ttl, ok := v.TTL[req.GetMethod()]
+	if !ok || ttl <= 0 || res.StatusCode != http.StatusOK {
+		return
+	}
+
+	err := v.Store.Set(ctx, v.Key(req), &Entry{
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+		Body:       body,
+		ExpiresAt:  time.Now().Add(ttl),
+	})
+	if err != nil {
+		v.error(err)
+	}
+}
+
+// Сообщает, получен ли HTTP ответ из кеша
+func IsHit(res *http.Response) bool {
+	return res != nil && res.Header.Get(HitHeader) != ""
+}
+
+func (v *Cache) error(err error) {
+	if v.OnError != nil {
+		v.OnError(err)
+	}
+}
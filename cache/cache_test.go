@@ -0,0 +1,168 @@
+package cache_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/cache"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Транспорт, считающий запросы и отвечающий заданным телом
+type countingRoundTripper struct {
+	calls int
+	body  string
+}
+
+func (v *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(v.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestCache(t *testing.T) {
+	newRequest := func(method string) *request.Request {
+		req := request.New()
+		req.Method(method)
+		req.GetParams().Set("group_ids", "1")
+		return req
+	}
+
+	newExecutor := func(rt http.RoundTripper, store cache.Store) *executor.Executor {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.Cache = cache.New(store)
+		return exec
+	}
+
+	t.Run("cached response passes through hooks", func(t *testing.T) {
+		rt := &countingRoundTripper{body: `{"response":[{"id":1}]}`}
+		exec := newExecutor(rt, cache.NewMemoryStore(10))
+
+		hits := 0
+		exec.HttpResponseHook(func(next executor.HttpResponseNextHook, res *http.Response) error {
+			if cache.IsHit(res) {
+				hits++
+			}
+			return next(res)
+		})
+
+		hooks := 0
+		exec.ApiResponseHook(func(next executor.ApiResponseNextHook, res response.Response) error {
+			hooks++
+			return next(res)
+		})
+
+		for i := 0; i < 3; i++ {
+			res, err := exec.DoRequest(newRequest("groups.getById"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.String() != rt.body {
+				t.Fatalf("unexpected response: %s", res.String())
+			}
+			if res.Context() == nil {
+				t.Fatalf("response context is nil")
+			}
+		}
+
+		if rt.calls != 1 {
+			t.Errorf("expected one http call, got %d", rt.calls)
+		}
+
+		if hits != 2 || hooks != 3 {
+			t.Errorf("unexpected hooks calls: hits %d, api hooks %d", hits, hooks)
+		}
+	})
+
+	t.Run("bypass reads fresh response", func(t *testing.T) {
+		rt := &countingRoundTripper{body: `{"response":[{"id":1}]}`}
+		exec := newExecutor(rt, cache.NewMemoryStore(10))
+
+		exec.DoRequest(newRequest("groups.getById"))
+		exec.DoRequestCtx(cache.WithBypass(context.Background()), newRequest("groups.getById"))
+
+		if rt.calls != 2 {
+			t.Errorf("expected two http calls, got %d", rt.calls)
+		}
+	})
+
+	t.Run("errors and other methods are not cached", func(t *testing.T) {
+		rt := &countingRoundTripper{body: `{"error":{"error_code":10,"error_msg":"Internal server error"}}`}
+		exec := newExecutor(rt, cache.NewMemoryStore(10))
+
+		exec.DoRequest(newRequest("groups.getById"))
+		exec.DoRequest(newRequest("groups.getById"))
+
+		rt.body = `{"response":1}`
+		exec.DoRequest(newRequest("groups.join"))
+		exec.DoRequest(newRequest("groups.join"))
+
+		if rt.calls != 4 {
+			t.Errorf("expected four http calls, got %d", rt.calls)
+		}
+	})
+
+	t.Run("disk store keeps responses", func(t *testing.T) {
+		store, err := cache.NewDiskStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rt := &countingRoundTripper{body: `{"response":{"type":"group","object_id":1}}`}
+		newExecutor(rt, store).DoRequest(newRequest("utils.resolveScreenName"))
+
+		res, err := newExecutor(rt, store).DoRequest(newRequest("utils.resolveScreenName"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rt.calls != 1 || res.String() != rt.body {
+			t.Errorf("expected cached response, calls %d, body %s", rt.calls, res.String())
+		}
+	})
+}
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("memory store evicts least recently used", func(t *testing.T) {
+		store := cache.NewMemoryStore(2)
+		store.Set(ctx, "a", &cache.Entry{Body: []byte("a")})
+		store.Set(ctx, "b", &cache.Entry{Body: []byte("b")})
+		store.Get(ctx, "a")
+		store.Set(ctx, "c", &cache.Entry{Body: []byte("c")})
+
+		if entry, _ := store.Get(ctx, "b"); entry != nil {
+			t.Errorf("entry b must be evicted")
+		}
+
+		if entry, _ := store.Get(ctx, "a"); entry == nil {
+			t.Errorf("entry a must be kept")
+		}
+	})
+
+	t.Run("expired entries are removed", func(t *testing.T) {
+		disk, err := cache.NewDiskStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, store := range []cache.Store{cache.NewMemoryStore(0), disk} {
+			store.Set(ctx, "key", &cache.Entry{Body: []byte("x"), ExpiresAt: time.Now().Add(-time.Second)})
+			if entry, err := store.Get(ctx, "key"); entry != nil || err != nil {
+				t.Errorf("expired entry returned: %v, %v", entry, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Хранилище ответов на диске. Каждый ответ хранится в отдельном JSON файле.
+// Просроченные ответы удаляются при чтении
+type DiskStore struct {
+	dir string
+}
+
+// Создает хранилище в директории dir, создавая ее при необходимости
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create cache dir error: %w", err)
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+// Реализует интерфейс Store
+func (v *DiskStore) Get(ctx context.Context, key string) (*Entry, error) {
+	data, err := os.ReadFile(v.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache entry error: %w", err)
+	}
+
+	entry := &Entry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		v.Delete(ctx, key)
+		return nil, fmt.Errorf("decode cache entry error: %w", err)
+	}
+
+	if entry.Expired(time.Now()) {
+		return nil, v.Delete(ctx, key)
+	}
+
+	return entry, nil
+}
+
+// Реализует интерфейс Store.
+// Файл записывается во временный файл и переименовывается, чтобы читатели не увидели его частично записанным
+func (v *DiskStore) Set(ctx context.Context, key string, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode cache entry error: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(v.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write cache entry error: %w", err)
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), v.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry error: %w", err)
+	}
+
+	return nil
+}
+
+// Реализует интерфейс Store
+func (v *DiskStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(v.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete cache entry error: %w", err)
+	}
+	return nil
+}
+
+func (v *DiskStore) path(key string) string {
+	return filepath.Join(v.dir, filepath.Base(key)+".json")
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Хранилище ответов в памяти с вытеснением давно неиспользуемых ответов (LRU)
+type MemoryStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// Элемент списка вытеснения
+type memoryItem struct {
+	key   string
+	entry *Entry
+}
+
+// Создает хранилище в памяти.
+// maxEntries - максимальное количество ответов, 0 - без ограничения
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// Реализует интерфейс Store
+func (v *MemoryStore) Get(ctx context.Context, key string) (*Entry, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	element, ok := v.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	item := element.Value.(*memoryItem)
+	if item.entry.Expired(time.Now()) {
+		v.remove(element)
+		return nil, nil
+	}
+
+	v.order.MoveToFront(element)
+	return item.entry, nil
+}
+
+// Реализует интерфейс Store
+func (v *MemoryStore) Set(ctx context.Context, key string, entry *Entry) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if element, ok := v.entries[key]; ok {
+		element.Value.(*memoryItem).entry = entry
+		v.order.MoveToFront(element)
+		return nil
+	}
+
+	v.entries[key] = v.order.PushFront(&memoryItem{key: key, entry: entry})
+
+	if v.maxEntries > 0 {
+		for v.order.Len() > v.maxEntries {
+			v.remove(v.order.Back())
+		}
+	}
+
+	return nil
+}
+
+// Реализует интерфейс Store
+func (v *MemoryStore) Delete(ctx context.Context, key string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if element, ok := v.entries[key]; ok {
+		v.remove(element)
+	}
+
+	return nil
+}
+
+// Возвращает количество сохраненных ответов
+func (v *MemoryStore) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.order.Len()
+}
+
+func (v *MemoryStore) remove(element *list.Element) {
+	v.order.Remove(element)
+	delete(v.entries, element.Value.(*memoryItem).key)
+}
@@ -0,0 +1,183 @@
+// Пакет captcha реализует решение капчи (ошибка VK API 14) и переотправку запроса с ее ключом
+package captcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Код ошибки VK API "Captcha needed"
+const ErrorCodeCaptchaNeeded = int(response.ErrorCodeCaptchaNeeded)
+
+var (
+	CaptchaSidParamKey = "captcha_sid"
+	CaptchaKeyParamKey = "captcha_key"
+)
+
+// Максимальный размер изображения капчи
+var MaxImageSize int64 = 1 << 20
+
+// Возвращается решателем, если капчу решить не удалось
+var ErrNotSolved = errors.New("captcha not solved")
+
+// Решатель капчи: получает изображение и возвращает ключ
+type Solver interface {
+	Solve(ctx context.Context, image []byte) (string, error)
+}
+
+// Позволяет использовать функцию как решатель капчи
+type SolverFunc func(ctx context.Context, image []byte) (string, error)
+
+func (f SolverFunc) Solve(ctx context.Context, image []byte) (string, error) {
+	return f(ctx, image)
+}
+
+// Хук API ответа, решающий капчу.
+// При ошибке 14 скачивает изображение капчи, передает его решателю
+// и переотправляет запрос с параметрами captcha_sid и captcha_key.
+// Параметры капчи добавляются в копию запроса, которую executor создает для каждого вызова,
+// поэтому общий шаблон запроса не изменяется и параметры не попадают в следующие вызовы.
+// Количество решений на один токен ограничено MaxAttempts за время Window, после чего ошибка капчи возвращается как есть
+type Hook struct {
+	Solver      Solver        // Решатель капчи
+	HttpClient  *http.Client  // HTTP клиент для загрузки изображений капчи
+	MaxAttempts int           // Максимальное количество решений капчи на токен за Window, 0 - без ограничения
+	Window      time.Duration // Период, за который считаются решения капчи
+
+	mu       sync.Mutex
+	attempts map[string]*tokenAttempts
+}
+
+// Количество решений капчи токеном в текущем периоде
+type tokenAttempts struct {
+	count int
+	reset time.Time
+}
+
+// Создает хук решения капчи
+//
+//	exec.ApiResponseHook(captcha.New(solver).ApiResponseHook)
+func New(solver Solver) *Hook {
+	return &Hook{
+		Solver:      solver,
+		HttpClient:  http.DefaultClient,
+		MaxAttempts: 5,
+		Window:      time.Hour,
+		attempts:    map[string]*tokenAttempts{},
+	}
+}
+
+// Реализует executor.ApiResponseHook
+func (v *Hook) ApiResponseHook(next executor.ApiResponseNextHook, res response.Response) error {
+	ctx := res.Context()
+	req := executor.GetRequest(ctx)
+
+	var apiError *response.Error
+	if !errors.As(res.Error(), &apiError) || apiError.IntCode() != ErrorCodeCaptchaNeeded || req == nil {
+		if req != nil {
+			clearCaptchaParams(req)
+		}
+		return next(res)
+	}
+
+	if apiError.CaptchaImg == "" || !v.allow(req.GetToken()) {
+		return next(res)
+	}
+
+	image, err := v.downloadImage(ctx, apiError.CaptchaImg)
+	if err != nil {
+		return err
+	}
+
+	key, err := v.Solver.Solve(ctx, image)
+	if err != nil {
+		return fmt.Errorf("solve captcha error: %w", err)
+	}
+
+	params := req.GetParams()
+	if params == nil {
+		params = request.NewParams()
+		req.Params(params)
+	}
+	params.Set(CaptchaSidParamKey, apiError.CaptchaSid)
+	params.Set(CaptchaKeyParamKey, key)
+
+	res.Renew(true)
+	return next(res)
+}
+
+// Учитывает попытку решения капчи для токена. Возвращает false, если лимит исчерпан
+func (v *Hook) allow(token string) bool {
+	if v.MaxAttempts <= 0 {
+		return true
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.attempts == nil {
+		v.attempts = map[string]*tokenAttempts{}
+	}
+
+	now := time.Now()
+	attempts, ok := v.attempts[token]
+	if !ok || now.After(attempts.reset) {
+		attempts = &tokenAttempts{reset: now.Add(v.Window)}
+		v.attempts[token] = attempts
+	}
+
+	if attempts.count >= v.MaxAttempts {
+		return false
+	}
+
+	attempts.count++
+	return true
+}
+
+// Скачивает изображение капчи
+func (v *Hook) downloadImage(ctx context.Context, imageUrl string) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, imageUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("captcha image request error: %w", err)
+	}
+
+	client := v.HttpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("captcha image download error: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("captcha image download error: status %d", res.StatusCode)
+	}
+
+	image, err := io.ReadAll(response.LimitBody(res.Body, MaxImageSize))
+	if err != nil {
+		return nil, fmt.Errorf("captcha image download error: %w", err)
+	}
+
+	return image, nil
+}
+
+// Удаляет параметры решенной капчи из запроса, чтобы они не попали в следующие попытки
+func clearCaptchaParams(req *request.Request) {
+	params := req.GetParams()
+	if params != nil && params.Has(CaptchaSidParamKey) {
+		params.Del(CaptchaSidParamKey)
+		params.Del(CaptchaKeyParamKey)
+	}
+}
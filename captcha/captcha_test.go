@@ -0,0 +1,209 @@
+package captcha_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/captcha"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Сервер, требующий капчу с ключом "42" для каждого запроса
+func newCaptchaServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	mu := sync.Mutex{}
+	var keys []string
+
+	server := httptest.NewServer(nil)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/captcha.php" {
+			w.Write([]byte("image:" + r.URL.Query().Get("sid")))
+			return
+		}
+
+		r.ParseForm()
+		mu.Lock()
+		keys = append(keys, r.PostForm.Get("captcha_key"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("captcha_sid") == "100" && r.PostForm.Get("captcha_key") == "42" {
+			w.Write([]byte(`{"response":1}`))
+			return
+		}
+		w.Write([]byte(`{"error":{"error_code":14,"error_msg":"Captcha needed","captcha_sid":"100",` +
+			`"captcha_img":"` + server.URL + `/captcha.php?sid=100"}}`))
+	})
+
+	return server, &keys
+}
+
+func TestHook(t *testing.T) {
+	newRequest := func(server *httptest.Server) *request.Request {
+		req := request.New()
+		req.Method("wall.post")
+		req.BaseUrl(server.URL + "/method/")
+		req.GetParams().AccessToken("token")
+		return req
+	}
+
+	t.Run("solve captcha and renew request", func(t *testing.T) {
+		server, keys := newCaptchaServer(t)
+		defer server.Close()
+
+		var solvedImage string
+		hook := captcha.New(captcha.SolverFunc(func(ctx context.Context, image []byte) (string, error) {
+			solvedImage = string(image)
+			return "42", nil
+		}))
+
+		exec := executor.New()
+		exec.ApiResponseHook(hook.ApiResponseHook)
+
+		req := newRequest(server)
+		res, err := exec.DoRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != `{"response":1}` {
+			t.Errorf("unexpected response: %s", res)
+		}
+
+		if solvedImage != "image:100" {
+			t.Errorf("unexpected captcha image: %q", solvedImage)
+		}
+
+		if len(*keys) != 2 || (*keys)[1] != "42" {
+			t.Errorf("unexpected sent captcha keys: %v", *keys)
+		}
+
+		if _, err := exec.DoRequest(req); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(*keys) != 4 || (*keys)[2] != "" {
+			t.Errorf("captcha params not cleared after solving: %v", *keys)
+		}
+	})
+
+	t.Run("attempts limit per token", func(t *testing.T) {
+		server, keys := newCaptchaServer(t)
+		defer server.Close()
+
+		hook := captcha.New(captcha.SolverFunc(func(ctx context.Context, image []byte) (string, error) {
+			return "wrong", nil
+		}))
+		hook.MaxAttempts = 2
+
+		exec := executor.New()
+		exec.ApiResponseHook(hook.ApiResponseHook)
+
+		_, err := exec.DoRequest(newRequest(server))
+
+		var apiError *response.Error
+		if !errors.As(err, &apiError) || apiError.IntCode() != captcha.ErrorCodeCaptchaNeeded {
+			t.Errorf("expected captcha error, got %v", err)
+		}
+
+		if len(*keys) != 3 {
+			t.Errorf("expected 3 requests, got %d", len(*keys))
+		}
+	})
+
+	t.Run("do not change shared request", func(t *testing.T) {
+		server, _ := newCaptchaServer(t)
+		defer server.Close()
+
+		hook := captcha.New(captcha.SolverFunc(func(ctx context.Context, image []byte) (string, error) {
+			return "wrong", nil
+		}))
+		hook.MaxAttempts = 0
+
+		exec := executor.New()
+		exec.MaxRenews = 2
+		exec.ApiResponseHook(hook.ApiResponseHook)
+
+		template := newRequest(server)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := exec.DoRequest(template); !errors.Is(err, executor.ErrRenewLimitExceeded) {
+					t.Errorf("expected renew limit error, got %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if template.GetParams().Has(captcha.CaptchaSidParamKey) || template.GetParams().Has(captcha.CaptchaKeyParamKey) {
+			t.Errorf("captcha params left in shared request: %s", template.GetParams())
+		}
+	})
+
+	t.Run("solver error", func(t *testing.T) {
+		server, _ := newCaptchaServer(t)
+		defer server.Close()
+
+		exec := executor.New()
+		exec.ApiResponseHook(captcha.New(captcha.SolverFunc(func(ctx context.Context, image []byte) (string, error) {
+			return "", captcha.ErrNotSolved
+		})).ApiResponseHook)
+
+		if _, err := exec.DoRequest(newRequest(server)); !errors.Is(err, captcha.ErrNotSolved) {
+			t.Errorf("expected solver error, got %v", err)
+		}
+	})
+}
+
+func TestManualSolver(t *testing.T) {
+	t.Run("operator resolves task", func(t *testing.T) {
+		server, _ := newCaptchaServer(t)
+		defer server.Close()
+
+		solver := captcha.NewManualSolver(1)
+		go func() {
+			task := <-solver.Tasks()
+			if string(task.Image) != "image:100" {
+				task.Reject(errors.New("unexpected image"))
+				return
+			}
+			task.Resolve("42")
+		}()
+
+		exec := executor.New()
+		exec.ApiResponseHook(captcha.New(solver).ApiResponseHook)
+
+		req := request.New()
+		req.Method("wall.post")
+		req.BaseUrl(server.URL + "/method/")
+
+		if _, err := exec.DoRequest(req); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("context cancel while waiting", func(t *testing.T) {
+		solver := captcha.NewManualSolver(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := solver.Solve(ctx, []byte("image")); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+
+		task := <-solver.Tasks()
+		task.Resolve("late")
+	})
+}
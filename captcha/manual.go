@@ -0,0 +1,78 @@
+package captcha
+
+import (
+	"context"
+)
+
+// Задача ручного решения капчи
+type Task struct {
+	Image []byte // Изображение капчи
+
+	result chan taskResult
+}
+
+// Результат ручного решения
+type taskResult struct {
+	key string
+	err error
+}
+
+// Передает ключ капчи, введенный оператором
+func (t *Task) Resolve(key string) {
+	t.send(taskResult{key: key})
+}
+
+// Отказывается от решения капчи
+func (t *Task) Reject(err error) {
+	if err == nil {
+		err = ErrNotSolved
+	}
+	t.send(taskResult{err: err})
+}
+
+// Передает результат решения. Повторные результаты игнорируются
+func (t *Task) send(result taskResult) {
+	select {
+	case t.result <- result:
+	default:
+	}
+}
+
+// Решатель капчи людьми-операторами.
+// Каждая капча отправляется в канал Tasks(), оператор решает ее и вызывает Task.Resolve() или Task.Reject()
+type ManualSolver struct {
+	tasks chan *Task
+}
+
+// Создает решатель с очередью задач размера buffer
+func NewManualSolver(buffer int) *ManualSolver {
+	return &ManualSolver{
+		tasks: make(chan *Task, buffer),
+	}
+}
+
+// Возвращает канал задач для операторов
+func (v *ManualSolver) Tasks() <-chan *Task {
+	return v.tasks
+}
+
+// Отправляет капчу оператору и ждет решения или завершения контекста
+func (v *ManualSolver) Solve(ctx context.Context, image []byte) (string, error) {
+	task := &Task{
+		Image:  image,
+		result: make(chan taskResult, 1),
+	}
+
+	select {
+	case v.tasks <- task:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	select {
+	case result := <-task.result:
+		return result.key, result.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,59 @@
+package circuitbreaker
+
+import (
+	"io"
+	"sync"
+
+	"github.com/buger/jsonparser"
+)
+
+// Размер начала тела ответа, в котором ищется код ошибки VK API
+const outcomePrefixSize = 512
+
+// Тело ответа, сообщающее код ошибки VK API после чтения до конца или закрытия.
+// Сохраняет только начало тела, поэтому не мешает потоковому разбору ответа
+type outcomeBody struct {
+	io.ReadCloser
+	prefix []byte
+	once   sync.Once
+	done   func(code int)
+}
+
+func newOutcomeBody(body io.ReadCloser, done func(code int)) *outcomeBody {
+	return &outcomeBody{
+		ReadCloser: body,
+		prefix:     make([]byte, 0, outcomePrefixSize),
+		done:       done,
+	}
+}
+
+func (b *outcomeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if free := outcomePrefixSize - len(b.prefix); free > 0 {
+		if n < free {
+			free = n
+		}
+		b.prefix = append(b.prefix, p[:free]...)
+	}
+
+	if err == io.EOF {
+		b.finish()
+	}
+
+	return n, err
+}
+
+func (b *outcomeBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *outcomeBody) finish() {
+	b.once.Do(func() {
+		code, err := jsonparser.GetInt(b.prefix, "error", "error_code")
+		if err != nil {
+			code = 0
+		}
+		b.done(int(code))
+	})
+}
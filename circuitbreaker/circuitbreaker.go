@@ -0,0 +1,301 @@
+// Пакет circuitbreaker реализует предохранитель (circuit breaker) для запросов к VK API.
+// Предохранитель отслеживает долю ошибок по ключу (методу, семейству методов или токену)
+// и при ее превышении перестает отправлять запросы, сразу возвращая ErrCircuitOpen
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Возвращается вместо отправки запроса, если предохранитель по ключу запроса разомкнут.
+// Оборачивает executor.ErrNotRetryable, поэтому executor не повторяет такой запрос
+var ErrCircuitOpen = fmt.Errorf("circuit breaker is open: %w", executor.ErrNotRetryable)
+
+// Ошибка разомкнутого предохранителя с информацией о ключе и времени следующей пробы
+type OpenError struct {
+	Key   string    // Ключ предохранителя
+	Until time.Time // Время, после которого будет разрешен пробный запрос
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s: key %q", ErrCircuitOpen, e.Key)
+}
+
+func (e *OpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// Состояние предохранителя
+type State int
+
+const (
+	StateClosed   State = iota // Запросы отправляются, ошибки подсчитываются
+	StateOpen                  // Запросы отклоняются с ErrCircuitOpen
+	StateHalfOpen              // Отправляются только пробные запросы
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Коды ошибок VK API, считающиеся отказом по умолчанию:
+// 1 - неизвестная ошибка, 10 - внутренняя ошибка сервера
+func DefaultFailureCodes() map[int]bool {
+	return map[int]bool{
+		int(response.ErrorCodeUnknown):        true,
+		int(response.ErrorCodeInternalServer): true,
+	}
+}
+
+// Предохранитель запросов к VK API. Подключается как транспорт HTTP клиента executor'а, аналогично limiter.Tripper.
+// Отказом считаются сетевые ошибки, ответы 5xx и ошибки VK API с кодами из FailureCodes.
+// Когда за интервал Interval набирается не меньше MinRequests запросов и доля отказов достигает FailureRatio,
+// предохранитель размыкается на OpenTimeout. Затем разрешается HalfOpenProbes пробных запросов:
+// если все они успешны, предохранитель замыкается, иначе снова размыкается
+//
+//	breaker := circuitbreaker.New()
+//	exec.HttpClient = &http.Client{Transport: breaker}
+type Breaker struct {
+	// Возвращает ключ предохранителя для запроса. Запросы с пустым ключом не ограничиваются
+	Key KeyFunc
+	// Доля отказов, при которой предохранитель размыкается
+	FailureRatio float64
+	// Минимальное количество запросов за интервал, после которого оценивается доля отказов
+	MinRequests int
+	// Интервал подсчета запросов в замкнутом состоянии
+	Interval time.Duration
+	// Время, на которое предохранитель размыкается
+	OpenTimeout time.Duration
+	// Количество успешных пробных запросов для замыкания предохранителя
+	HalfOpenProbes int
+	// Коды ошибок VK API, считающиеся отказом
+	FailureCodes map[int]bool
+	// Вызывается при смене состояния предохранителя
+	OnStateChange func(key string, from, to State)
+	// Транспорт, через который отправляются разрешенные запросы
+	Tripper http.RoundTripper
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// Состояние предохранителя одного ключа
+type circuit struct {
+	state      State
+	generation uint64    // Увеличивается при смене состояния и сбросе счетчиков, чтобы игнорировать устаревшие результаты
+	since      time.Time // Начало интервала подсчета или время перехода в текущее состояние
+	requests   int
+	failures   int
+	probes     int // Пробные запросы в полуоткрытом состоянии
+	successes  int // Успешные пробные запросы
+}
+
+// Смена состояния для вызова OnStateChange вне блокировки
+type transition struct {
+	key      string
+	from, to State
+}
+
+// Создает предохранитель с ключом по методу и настройками по умолчанию
+func New() *Breaker {
+	return &Breaker{
+		Key:            KeyByMethod,
+		FailureRatio:   0.5,
+		MinRequests:    20,
+		Interval:       time.Minute,
+		OpenTimeout:    30 * time.Second,
+		HalfOpenProbes: 1,
+		FailureCodes:   DefaultFailureCodes(),
+		Tripper:        http.DefaultTransport,
+		circuits:       map[string]*circuit{},
+	}
+}
+
+// Возвращает текущее состояние предохранителя по ключу
+func (v *Breaker) State(key string) State {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if c, ok := v.circuits[key]; ok {
+		return c.state
+	}
+	return StateClosed
+}
+
+// Реализует http.RoundTripper
+func (v *Breaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	apiReq := executor.GetRequest(req.Context())
+	if apiReq == nil {
+		return v.Tripper.RoundTrip(req)
+	}
+
+	key := v.Key(apiReq)
+	if key == "" {
+		return v.Tripper.RoundTrip(req)
+	}
+
+	generation, err := v.allow(key)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := v.Tripper.RoundTrip(req)
+	if err != nil {
+		if req.Context().Err() != nil {
+			// Отмена запроса вызывающей стороной не говорит о состоянии API
+			v.release(key, generation)
+		} else {
+			v.record(key, generation, true)
+		}
+		return nil, err
+	}
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		v.record(key, generation, true)
+		return res, nil
+	}
+
+	res.Body = newOutcomeBody(res.Body, func(code int) {
+		v.record(key, generation, v.FailureCodes[code])
+	})
+
+	return res, nil
+}
+
+// Разрешает или отклоняет запрос по ключу, возвращая поколение состояния для записи результата
+func (v *Breaker) allow(key string) (uint64, error) {
+	v.mu.Lock()
+
+	now := time.Now()
+	c, ok := v.circuits[key]
+	if !ok {
+		c = &circuit{since: now}
+		v.circuits[key] = c
+	}
+
+	var changed *transition
+
+	switch c.state {
+	case StateClosed:
+		if now.Sub(c.since) >= v.Interval {
+			c.reset(now)
+		}
+	case StateOpen:
+		until := c.since.Add(v.OpenTimeout)
+		if now.Before(until) {
+			v.mu.Unlock()
+			return 0, &OpenError{Key: key, Until: until}
+		}
+		changed = c.set(key, StateHalfOpen, now)
+	case StateHalfOpen:
+		// Пробы, результат которых так и не был получен, не должны блокировать ключ навсегда
+		if c.probes >= v.HalfOpenProbes && now.Sub(c.since) >= v.OpenTimeout {
+			c.reset(now)
+		}
+	}
+
+	if c.state == StateHalfOpen {
+		if c.probes >= v.HalfOpenProbes {
+			until := c.since.Add(v.OpenTimeout)
+			v.mu.Unlock()
+			v.notify(changed)
+			return 0, &OpenError{Key: key, Until: until}
+		}
+		c.probes++
+	}
+
+	generation := c.generation
+	v.mu.Unlock()
+	v.notify(changed)
+
+	return generation, nil
+}
+
+// Записывает результат запроса
+func (v *Breaker) record(key string, generation uint64, failure bool) {
+	v.mu.Lock()
+
+	c, ok := v.circuits[key]
+	if !ok || c.generation != generation {
+		v.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	var changed *transition
+
+	switch c.state {
+	case StateClosed:
+		c.requests++
+		if failure {
+			c.failures++
+		}
+		if c.requests >= v.MinRequests && float64(c.failures) >= v.FailureRatio*float64(c.requests) {
+			changed = c.set(key, StateOpen, now)
+		}
+	case StateHalfOpen:
+		if failure {
+			changed = c.set(key, StateOpen, now)
+		} else if c.successes++; c.successes >= v.HalfOpenProbes {
+			changed = c.set(key, StateClosed, now)
+		}
+	}
+
+	v.mu.Unlock()
+	v.notify(changed)
+}
+
+// Освобождает пробу без записи результата
+func (v *Breaker) release(key string, generation uint64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if c, ok := v.circuits[key]; ok && c.generation == generation && c.state == StateHalfOpen && c.probes > 0 {
+		c.probes--
+	}
+}
+
+// Вызывает OnStateChange
+func (v *Breaker) notify(changed *transition) {
+	if changed != nil && v.OnStateChange != nil {
+		v.OnStateChange(changed.key, changed.from, changed.to)
+	}
+}
+
+// Переводит предохранитель в новое состояние
+func (c *circuit) set(key string, state State, now time.Time) *transition {
+	changed := &transition{key: key, from: c.state, to: state}
+	c.state = state
+	c.reset(now)
+	return changed
+}
+
+// Сбрасывает счетчики текущего состояния
+func (c *circuit) reset(now time.Time) {
+	c.generation++
+	c.since = now
+	c.requests = 0
+	c.failures = 0
+	c.probes = 0
+	c.successes = 0
+}
+
+// Проверяет, что ошибка вызвана разомкнутым предохранителем
+func IsOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
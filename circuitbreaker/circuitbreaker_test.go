@@ -0,0 +1,192 @@
+package circuitbreaker_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/circuitbreaker"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Транспорт, отвечающий ошибкой VK API с кодом 10, пока failing установлен
+type methodRoundTripper struct {
+	mu      sync.Mutex
+	failing bool
+	calls   int
+}
+
+func (v *methodRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.calls++
+	body := `{"response":1}`
+	if v.failing {
+		body = `{"error":{"error_code":10,"error_msg":"Internal server error"}}`
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestBreaker(t *testing.T) {
+	newRequest := func(method string) *request.Request {
+		req := request.New()
+		req.Method(method)
+		return req
+	}
+
+	t.Run("opens after failures and closes after probe", func(t *testing.T) {
+		rt := &methodRoundTripper{failing: true}
+
+		var transitions []string
+		breaker := circuitbreaker.New()
+		breaker.Key = circuitbreaker.KeyByMethodFamily
+		breaker.MinRequests = 3
+		breaker.OpenTimeout = 50 * time.Millisecond
+		breaker.Tripper = rt
+		breaker.OnStateChange = func(key string, from, to circuitbreaker.State) {
+			transitions = append(transitions, key+":"+from.String()+"->"+to.String())
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: breaker}
+		exec.RetryPolicy = executor.NewBackoffRetryPolicy(5)
+
+		for i := 0; i < 3; i++ {
+			if _, err := exec.DoRequest(newRequest("photos.get")); err == nil {
+				t.Fatal("expected api error")
+			}
+		}
+
+		if breaker.State("photos") != circuitbreaker.StateOpen {
+			t.Fatalf("expected open state, got %s", breaker.State("photos"))
+		}
+
+		_, err := exec.DoRequest(newRequest("photos.save"))
+		if !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+			t.Fatalf("expected ErrCircuitOpen, got %v", err)
+		}
+
+		var openErr *circuitbreaker.OpenError
+		if !errors.As(err, &openErr) || openErr.Key != "photos" {
+			t.Errorf("expected open error for photos, got %v", err)
+		}
+
+		if rt.calls != 3 {
+			t.Errorf("open circuit must fail fast without retries, calls: %d", rt.calls)
+		}
+
+		if _, err := exec.DoRequest(newRequest("users.get")); errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+			t.Errorf("other method family must not be affected")
+		}
+
+		time.Sleep(60 * time.Millisecond)
+		rt.failing = false
+
+		if _, err := exec.DoRequest(newRequest("photos.get")); err != nil {
+			t.Fatalf("probe request failed: %v", err)
+		}
+
+		if breaker.State("photos") != circuitbreaker.StateClosed {
+			t.Errorf("expected closed state, got %s", breaker.State("photos"))
+		}
+
+		want := []string{"photos:closed->open", "photos:open->half-open", "photos:half-open->closed"}
+		if strings.Join(transitions, ",") != strings.Join(want, ",") {
+			t.Errorf("unexpected transitions: %v", transitions)
+		}
+	})
+
+	t.Run("token key does not expose token", func(t *testing.T) {
+		rt := &methodRoundTripper{failing: true}
+
+		var keys []string
+		breaker := circuitbreaker.New()
+		breaker.Key = circuitbreaker.KeyByToken
+		breaker.MinRequests = 1
+		breaker.Tripper = rt
+		breaker.OnStateChange = func(key string, from, to circuitbreaker.State) {
+			keys = append(keys, key)
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: breaker}
+
+		req := newRequest("users.get")
+		req.GetParams().AccessToken("secret_token")
+
+		exec.DoRequest(req)
+		_, err := exec.DoRequest(req)
+		if !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+			t.Fatalf("expected ErrCircuitOpen, got %v", err)
+		}
+
+		if strings.Contains(err.Error(), "secret_token") || strings.Contains(strings.Join(keys, ","), "secret_token") {
+			t.Errorf("token leaked: %v, keys %v", err, keys)
+		}
+
+		if breaker.State(executor.TokenId(req)) != circuitbreaker.StateOpen {
+			t.Errorf("expected open state by token id")
+		}
+	})
+
+	t.Run("failed probe opens circuit again", func(t *testing.T) {
+		rt := &methodRoundTripper{failing: true}
+
+		breaker := circuitbreaker.New()
+		breaker.MinRequests = 1
+		breaker.OpenTimeout = 20 * time.Millisecond
+		breaker.Tripper = rt
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: breaker}
+
+		exec.DoRequest(newRequest("photos.get"))
+		time.Sleep(30 * time.Millisecond)
+		exec.DoRequest(newRequest("photos.get"))
+
+		if breaker.State("photos.get") != circuitbreaker.StateOpen {
+			t.Errorf("expected open state, got %s", breaker.State("photos.get"))
+		}
+	})
+
+	t.Run("non failure codes keep circuit closed", func(t *testing.T) {
+		breaker := circuitbreaker.New()
+		breaker.MinRequests = 1
+		breaker.Tripper = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"error":{"error_code":100,"error_msg":"One of the parameters specified was missing or invalid"}}`)),
+				Request:    req,
+			}, nil
+		})
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: breaker}
+
+		for i := 0; i < 3; i++ {
+			exec.DoRequest(newRequest("users.get"))
+		}
+
+		if breaker.State("users.get") != circuitbreaker.StateClosed {
+			t.Errorf("expected closed state, got %s", breaker.State("users.get"))
+		}
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
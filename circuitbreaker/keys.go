@@ -0,0 +1,36 @@
+package circuitbreaker
+
+import (
+	"strings"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Возвращает ключ предохранителя для запроса
+type KeyFunc func(req *request.Request) string
+
+// Ключ по имени метода, например photos.get
+func KeyByMethod(req *request.Request) string {
+	return req.GetMethod()
+}
+
+// Ключ по семейству методов, например photos для photos.get и photos.save
+func KeyByMethodFamily(req *request.Request) string {
+	method := req.GetMethod()
+	if i := strings.IndexByte(method, '.'); i >= 0 {
+		return method[:i]
+	}
+	return method
+}
+
+// Ключ по токену доступа или анонимному токену.
+// Ключом служит хеш токена executor.TokenId, чтобы токен не попадал в ошибки и OnStateChange
+func KeyByToken(req *request.Request) string {
+	return executor.TokenId(req)
+}
+
+// Ключ по методу и токену: предохранитель срабатывает отдельно для каждой пары
+func KeyByMethodAndToken(req *request.Request) string {
+	return req.GetMethod() + "\x00" + KeyByToken(req)
+}
@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ciricc/vkapiexecutor/jsonresponseparser"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Максимальное количество вызовов API в одном запросе execute
+const MaxBatchSize = 25
+
+// Метод VK API для выполнения нескольких вызовов за один запрос
+const ExecuteMethod = "execute"
+
+var (
+	ErrBatchEmpty         = errors.New("batch is empty")
+	ErrBatchTooLarge      = fmt.Errorf("batch is larger than %d requests", MaxBatchSize)
+	ErrBatchTokenMismatch = errors.New("batch requests have different tokens")
+)
+
+// Допустимое имя метода VK API для вставки в код VKScript
+var batchMethodRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)+$`)
+
+// Выполняет до 25 запросов одним вызовом метода execute.
+// Все запросы должны использовать один и тот же токен, общие параметры (токен, версия, язык)
+// берутся из первого запроса.
+// Возвращает ответы в порядке запросов, ошибка каждого вызова доступна через Error() его ответа.
+// Ошибка возвращается, только если не удалось выполнить сам запрос execute
+func (v *Executor) DoBatch(ctx context.Context, reqs []*request.Request) ([]response.Response, error) {
+	executeReq, err := NewBatchRequest(reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, ok := v.ResponseParser.(*jsonresponseparser.JsonResponseParser)
+	if !ok {
+		parser = &jsonresponseparser.JsonResponseParser{}
+	}
+
+	res, err := v.DoRequestCtxParser(ctx, executeReq, parser)
+	if err != nil {
+		var executeErrors *response.ExecuteErrors
+		if res == nil || !errors.As(err, &executeErrors) {
+			return nil, err
+		}
+	}
+
+	jsonResponse, ok := res.(*jsonresponseparser.JsonResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected execute response type %T", res)
+	}
+
+	slots, err := jsonResponse.ExecuteResponses()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(slots) != len(reqs) {
+		return nil, fmt.Errorf("execute returned %d results for %d requests", len(slots), len(reqs))
+	}
+
+	responses := make([]response.Response, len(slots))
+	for i := range slots {
+		responses[i] = slots[i]
+	}
+
+	return responses, nil
+}
+
+// Создает запрос к методу execute, объединяющий переданные запросы.
+// Код VKScript возвращает массив результатов вызовов в порядке запросов
+func NewBatchRequest(reqs []*request.Request) (*request.Request, error) {
+	if len(reqs) == 0 {
+		return nil, ErrBatchEmpty
+	}
+
+	if len(reqs) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	code, err := BatchCode(reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	first := reqs[0].GetParams()
+	if first == nil {
+		first = request.NewParams()
+	}
+
+	for _, req := range reqs[1:] {
+		params := req.GetParams()
+		if params == nil {
+			params = request.NewParams()
+		}
+
+		if params.GetAccessToken() != first.GetAccessToken() || params.GetAnonymousToken() != first.GetAnonymousToken() {
+			return nil, ErrBatchTokenMismatch
+		}
+	}
+
+	params := request.NewParams()
+	for _, key := range batchCommonParams() {
+		if first.Has(key) {
+			params.Set(key, first.Get(key))
+		}
+	}
+	params.Set("code", code)
+
+	executeReq := request.New()
+	executeReq.Method(ExecuteMethod)
+	executeReq.BaseUrl(reqs[0].GetBaseUrl())
+	executeReq.Headers(reqs[0].GetHeaders().Clone())
+	executeReq.Params(params)
+
+	return executeReq, nil
+}
+
+// Генерирует код VKScript, вызывающий методы запросов и возвращающий массив их результатов
+func BatchCode(reqs []*request.Request) (string, error) {
+	calls := make([]string, len(reqs))
+
+	for i, req := range reqs {
+		method := req.GetMethod()
+		if !batchMethodRegexp.MatchString(method) {
+			return "", fmt.Errorf("invalid batch method %q", method)
+		}
+
+		args, err := batchCallArgs(req.GetParams())
+		if err != nil {
+			return "", err
+		}
+
+		calls[i] = "API." + method + "(" + args + ")"
+	}
+
+	return "return [" + strings.Join(calls, ",") + "];", nil
+}
+
+// Сериализует параметры вызова в объект VKScript без общих параметров запроса
+func batchCallArgs(params *request.Params) (string, error) {
+	if params == nil {
+		return "{}", nil
+	}
+
+	values := params.Values()
+	for _, key := range batchCommonParams() {
+		values.Del(key)
+	}
+
+	// Ключи объекта сортируются при сериализации, поэтому код одинаковых запросов совпадает
+	args := make(map[string]string, len(values))
+	for key, val := range values {
+		args[key] = strings.Join(val, ",")
+	}
+
+	buf := bytes.Buffer{}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(args); err != nil {
+		return "", fmt.Errorf("encode batch params error: %w", err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Параметры, которые передаются в сам запрос execute, а не в отдельные вызовы
+func batchCommonParams() []string {
+	return []string{
+		request.AccessTokenParamKey,
+		request.AnonymousTokenKey,
+		request.VersionParamKey,
+		request.LangParamKey,
+		request.DeviceIdParamKey,
+	}
+}
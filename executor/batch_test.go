@@ -0,0 +1,187 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+	"github.com/ciricc/vkapiexecutor/tokenpool"
+	"github.com/ciricc/vkapiexecutor/vktest"
+)
+
+func TestDoBatch(t *testing.T) {
+	newRequest := func(method string, params map[string]string) *request.Request {
+		req := request.New()
+		req.Method(method)
+		req.GetParams().AccessToken("token")
+		for key, val := range params {
+			req.GetParams().Set(key, val)
+		}
+		return req
+	}
+
+	t.Run("batch code", func(t *testing.T) {
+		code, err := executor.BatchCode([]*request.Request{
+			newRequest("users.get", map[string]string{"user_ids": "1,2", "fields": "photo_50"}),
+			newRequest("groups.getById", map[string]string{"group_id": "<b>\"1\""}),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := `return [API.users.get({"fields":"photo_50","user_ids":"1,2"}),API.groups.getById({"group_id":"<b>\"1\""})];`
+		if code != expected {
+			t.Errorf("expected code: %s\ngot: %s", expected, code)
+		}
+	})
+
+	t.Run("invalid batches", func(t *testing.T) {
+		exec := executor.New()
+
+		if _, err := exec.DoBatch(context.Background(), nil); !errors.Is(err, executor.ErrBatchEmpty) {
+			t.Errorf("expected empty batch error, got %v", err)
+		}
+
+		reqs := make([]*request.Request, executor.MaxBatchSize+1)
+		for i := range reqs {
+			reqs[i] = newRequest("users.get", nil)
+		}
+		if _, err := exec.DoBatch(context.Background(), reqs); !errors.Is(err, executor.ErrBatchTooLarge) {
+			t.Errorf("expected too large batch error, got %v", err)
+		}
+
+		otherToken := newRequest("users.get", nil)
+		otherToken.GetParams().AccessToken("other")
+		if _, err := exec.DoBatch(context.Background(), []*request.Request{reqs[0], otherToken}); !errors.Is(err, executor.ErrBatchTokenMismatch) {
+			t.Errorf("expected token mismatch error, got %v", err)
+		}
+
+		if _, err := exec.DoBatch(context.Background(), []*request.Request{newRequest("users.get}); API.x(", nil)}); err == nil {
+			t.Errorf("expected invalid method error")
+		}
+	})
+
+	t.Run("split execute response", func(t *testing.T) {
+		var sentMethod, sentCode, sentToken string
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				func(req *http.Request) (*http.Response, error) {
+					req.ParseForm()
+					sentMethod = req.URL.Path
+					sentCode = req.PostForm.Get("code")
+					sentToken = req.PostForm.Get("access_token")
+					return textResponse(http.StatusOK, "application/json", `{"response":[[{"id":1}],false,"text",false],`+
+						`"execute_errors":[{"method":"groups.getById","error_code":100,"error_msg":"One of the parameters specified was missing or invalid"},`+
+						`{"method":"wall.post","error_code":14,"error_msg":"Captcha needed","captcha_sid":"1"}]}`)(req)
+				},
+			},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		reqs := []*request.Request{
+			newRequest("users.get", map[string]string{"user_ids": "1"}),
+			newRequest("groups.getById", nil),
+			newRequest("utils.getShortLink", nil),
+			newRequest("wall.post", nil),
+		}
+
+		responses, err := exec.DoBatch(context.Background(), reqs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if sentMethod != "/method/execute" || sentToken != "token" {
+			t.Errorf("unexpected execute request: %s, token %q", sentMethod, sentToken)
+		}
+
+		if sentCode == "" {
+			t.Errorf("execute code not sent")
+		}
+
+		if len(responses) != len(reqs) {
+			t.Fatalf("expected %d responses, got %d", len(reqs), len(responses))
+		}
+
+		if responses[0].String() != `{"response":[{"id":1}]}` || responses[0].Error() != nil {
+			t.Errorf("unexpected first response: %s, %v", responses[0], responses[0].Error())
+		}
+
+		if responses[2].String() != `{"response":"text"}` || responses[2].Error() != nil {
+			t.Errorf("unexpected third response: %s, %v", responses[2], responses[2].Error())
+		}
+
+		expectedCodes := map[int]int{1: 100, 3: 14}
+		for i, code := range expectedCodes {
+			var apiError *response.Error
+			if !errors.As(responses[i].Error(), &apiError) || apiError.IntCode() != code {
+				t.Errorf("response %d: expected error code %d, got %v", i, code, responses[i].Error())
+			}
+		}
+	})
+
+	t.Run("execute error", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				textResponse(http.StatusOK, "application/json", `{"error":{"error_code":5,"error_msg":"User authorization failed"}}`),
+			},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		_, err := exec.DoBatch(context.Background(), []*request.Request{newRequest("users.get", nil)})
+
+		var apiError *response.Error
+		if !errors.As(err, &apiError) || apiError.IntCode() != 5 {
+			t.Errorf("expected execute api error, got %v", err)
+		}
+	})
+
+	t.Run("partial slot failure", func(t *testing.T) {
+		srv := vktest.NewServer()
+		defer srv.Close()
+
+		srv.OnExecute().ReturnExecute([]any{[]any{map[string]any{"id": 1}}, false, false, 1},
+			vktest.ExecuteError{Method: "users.get", Code: 30, Msg: "This profile is private"},
+			vktest.ExecuteError{Method: "wall.get", Code: 10, Msg: "Internal server error"},
+		)
+
+		pool := tokenpool.New("a", "b")
+
+		exec := srv.NewExecutor()
+		exec.Tokens = pool
+		exec.ApiResponseHook(executor.NewErrorCodeHook(executor.ErrorCodePolicies{
+			10: {MaxAttempts: 3},
+		}))
+
+		responses, err := exec.DoBatch(context.Background(), []*request.Request{
+			newRequest("users.get", map[string]string{"user_ids": "1"}),
+			newRequest("users.get", map[string]string{"user_ids": "2"}),
+			newRequest("wall.get", nil),
+			newRequest("wall.post", nil),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		srv.AssertCallCount(t, executor.ExecuteMethod, 1)
+
+		if pool.Quarantined("a") || pool.Quarantined("b") {
+			t.Errorf("slot error must not quarantine batch token")
+		}
+
+		if !errors.Is(responses[1].Error(), response.ErrPrivateProfile) || !errors.Is(responses[2].Error(), response.ErrInternalServer) {
+			t.Errorf("unexpected slot errors: %v, %v", responses[1].Error(), responses[2].Error())
+		}
+
+		if responses[0].Error() != nil || responses[3].Error() != nil {
+			t.Errorf("unexpected errors of successful slots: %v, %v", responses[0].Error(), responses[3].Error())
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Время ожидания новых запросов в очереди Batcher'а по умолчанию
+var DefaultBatchWindow = 20 * time.Millisecond
+
+// Автоматически объединяет запросы из разных горутин в вызовы execute.
+// Запросы с одинаковым токеном, версией API, языком и HTTP заголовками копятся в очереди,
+// которая отправляется одним запросом execute, как только в ней наберется 25 запросов
+// или пройдет время Window с момента постановки первого из них
+type Batcher struct {
+	executor *Executor
+	window   time.Duration
+
+	mu     sync.Mutex
+	queues map[string]*batchQueue
+	closed bool
+}
+
+// Очередь запросов с общим токеном
+type batchQueue struct {
+	calls []*batchCall
+	timer *time.Timer
+}
+
+// Запрос, ожидающий отправки в составе execute
+type batchCall struct {
+	ctx    context.Context
+	req    *request.Request
+	result chan batchResult
+}
+
+// Результат выполнения запроса в составе execute
+type batchResult struct {
+	res response.Response
+	err error
+}
+
+// Создает Batcher, отправляющий запросы через executor.
+// window - максимальное время ожидания запросов в очереди, по умолчанию DefaultBatchWindow
+func NewBatcher(exec *Executor, window time.Duration) *Batcher {
+	if window <= 0 {
+		window = DefaultBatchWindow
+	}
+
+	return &Batcher{
+		executor: exec,
+		window:   window,
+		queues:   map[string]*batchQueue{},
+	}
+}
+
+// Выполняет запрос в составе ближайшего вызова execute.
+// Возвращает ответ и ошибку так же, как executor.DoRequestCtx(), при этом контекст ответа
+// относится к запросу execute. Запросы к execute и запросы после Close() выполняются напрямую
+func (v *Batcher) DoRequestCtx(ctx context.Context, req *request.Request) (response.Response, error) {
+	if req == nil {
+		return nil, fmt.Errorf("input request empty")
+	}
+
+	if req.GetMethod() == ExecuteMethod {
+		return v.executor.DoRequestCtx(ctx, req)
+	}
+
+	call := &batchCall{
+		ctx:    ctx,
+		req:    req,
+		result: make(chan batchResult, 1),
+	}
+
+	if !v.enqueue(call) {
+		return v.executor.DoRequestCtx(ctx, req)
+	}
+
+	select {
+	case result := <-call.result:
+		return result.res, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Выполняет запрос в составе ближайшего вызова execute
+func (v *Batcher) DoRequest(req *request.Request) (response.Response, error) {
+	return v.DoRequestCtx(context.Background(), req)
+}
+
+// Немедленно отправляет все накопленные очереди
+func (v *Batcher) Flush() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for key := range v.queues {
+		v.flushLocked(key)
+	}
+}
+
+// Отправляет накопленные очереди и переключает Batcher на прямое выполнение запросов
+func (v *Batcher) Close() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.closed = true
+	for key := range v.queues {
+		v.flushLocked(key)
+	}
+}
+
+// Добавляет запрос в очередь. Возвращает false, если Batcher закрыт
+func (v *Batcher) enqueue(call *batchCall) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.closed {
+		return false
+	}
+
+	key := batchKey(call.req)
+	queue, ok := v.queues[key]
+	if !ok {
+		queue = &batchQueue{}
+		queue.timer = time.AfterFunc(v.window, func() {
+			v.mu.Lock()
+			defer v.mu.Unlock()
+
+			if v.queues[key] == queue {
+				v.flushLocked(key)
+			}
+		})
+		v.queues[key] = queue
+	}
+
+	queue.calls = append(queue.calls, call)
+	if len(queue.calls) >= MaxBatchSize {
+		v.flushLocked(key)
+	}
+
+	return true
+}
+
+// Убирает очередь и отправляет ее запросы. Вызывается с захваченным мьютексом
+func (v *Batcher) flushLocked(key string) {
+	queue := v.queues[key]
+	delete(v.queues, key)
+
+	queue.timer.Stop()
+	go v.send(queue.calls)
+}
+
+// Отправляет запросы одним вызовом execute и раздает результаты
+func (v *Batcher) send(calls []*batchCall) {
+	active := make([]*batchCall, 0, len(calls))
+	for _, call := range calls {
+		if call.ctx.Err() == nil {
+			active = append(active, call)
+		}
+	}
+
+	if len(active) == 0 {
+		return
+	}
+
+	reqs := make([]*request.Request, len(active))
+	for i, call := range active {
+		reqs[i] = call.req
+	}
+
+	ctx, cancel := batchContext(active)
+	defer cancel()
+
+	responses, err := v.executor.DoBatch(ctx, reqs)
+	for i, call := range active {
+		if err != nil {
+			call.result <- batchResult{err: err}
+			continue
+		}
+		call.result <- batchResult{res: responses[i], err: responses[i].Error()}
+	}
+}
+
+// Возвращает контекст запроса execute. Значения берутся из контекста первого запроса,
+// а отменяется он, только когда отменены контексты всех запросов
+func batchContext(calls []*batchCall) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(calls[0].ctx))
+
+	left := int32(len(calls))
+	stops := make([]func() bool, len(calls))
+	for i, call := range calls {
+		stops[i] = context.AfterFunc(call.ctx, func() {
+			if atomic.AddInt32(&left, -1) == 0 {
+				cancel()
+			}
+		})
+	}
+
+	return ctx, func() {
+		for _, stop := range stops {
+			stop()
+		}
+		cancel()
+	}
+}
+
+// Возвращает ключ очереди: в один execute попадают запросы с одинаковыми общими параметрами и заголовками,
+// так как заголовки запроса execute берутся из первого запроса
+func batchKey(req *request.Request) string {
+	params := req.GetParams()
+	if params == nil {
+		params = request.NewParams()
+	}
+
+	return strings.Join([]string{
+		req.GetBaseUrl(),
+		params.GetAccessToken(),
+		params.GetAnonymousToken(),
+		params.GetVersion(),
+		params.GetLang(),
+		params.GetDeviceId(),
+		headersKey(req.GetHeaders()),
+	}, "\x00")
+}
+
+// Возвращает заголовки строкой, не зависящей от порядка их установки
+func headersKey(headers http.Header) string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		for _, value := range headers[key] {
+			b.WriteString("\x01")
+			b.WriteString(value)
+		}
+		b.WriteString("\x02")
+	}
+	return b.String()
+}
@@ -0,0 +1,236 @@
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Транспорт, отвечающий на execute массивом из номеров вызовов
+type executeRoundTripper struct {
+	mu         sync.Mutex
+	batchSizes []int
+}
+
+func (v *executeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	calls := strings.Count(req.PostForm.Get("code"), "API.")
+
+	v.mu.Lock()
+	v.batchSizes = append(v.batchSizes, calls)
+	v.mu.Unlock()
+
+	results := make([]string, calls)
+	for i := range results {
+		results[i] = fmt.Sprint(i)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"response":[` + strings.Join(results, ",") + `]}`)),
+		Request:    req,
+	}, nil
+}
+
+func (v *executeRoundTripper) sizes() []int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]int(nil), v.batchSizes...)
+}
+
+// Транспорт, передающий контекст запроса в started и ждущий его отмены
+type waitCancelRoundTripper struct {
+	started chan context.Context
+}
+
+func (v *waitCancelRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v.started <- req.Context()
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestBatcher(t *testing.T) {
+	newRequest := func(token string) *request.Request {
+		req := request.New()
+		req.Method("users.get")
+		req.GetParams().AccessToken(token)
+		return req
+	}
+
+	t.Run("flush full batches and by window", func(t *testing.T) {
+		rt := &executeRoundTripper{}
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		batcher := executor.NewBatcher(exec, 50*time.Millisecond)
+		defer batcher.Close()
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 30; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				res, err := batcher.DoRequest(newRequest("token"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if !strings.HasPrefix(res.String(), `{"response":`) {
+					t.Errorf("unexpected response: %s", res)
+				}
+			}()
+		}
+		wg.Wait()
+
+		sizes := rt.sizes()
+		if len(sizes) != 2 || sizes[0]+sizes[1] != 30 || (sizes[0] != 25 && sizes[1] != 25) {
+			t.Errorf("expected batches of 25 and 5 requests, got %v", sizes)
+		}
+	})
+
+	t.Run("separate batches per token", func(t *testing.T) {
+		rt := &executeRoundTripper{}
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		batcher := executor.NewBatcher(exec, 10*time.Millisecond)
+		defer batcher.Close()
+
+		wg := sync.WaitGroup{}
+		for _, token := range []string{"a", "b", "a", "b"} {
+			wg.Add(1)
+			go func(token string) {
+				defer wg.Done()
+				if _, err := batcher.DoRequest(newRequest(token)); err != nil {
+					t.Error(err)
+				}
+			}(token)
+		}
+		wg.Wait()
+
+		sizes := rt.sizes()
+		if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 {
+			t.Errorf("expected two batches of 2 requests, got %v", sizes)
+		}
+	})
+
+	t.Run("caller context cancel", func(t *testing.T) {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: &executeRoundTripper{}}
+
+		batcher := executor.NewBatcher(exec, time.Hour)
+		defer batcher.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := batcher.DoRequestCtx(ctx, newRequest("token")); err != context.DeadlineExceeded {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("direct requests after close", func(t *testing.T) {
+		rt := &executeRoundTripper{}
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		batcher := executor.NewBatcher(exec, time.Hour)
+		batcher.Close()
+
+		if _, err := batcher.DoRequest(newRequest("token")); err != nil {
+			t.Error(err)
+		}
+
+		if len(rt.sizes()) != 1 || rt.sizes()[0] != 0 {
+			t.Errorf("expected direct request, got execute batches %v", rt.sizes())
+		}
+	})
+
+	t.Run("separate batches per headers", func(t *testing.T) {
+		rt := &executeRoundTripper{}
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		batcher := executor.NewBatcher(exec, 10*time.Millisecond)
+		defer batcher.Close()
+
+		wg := sync.WaitGroup{}
+		for _, agent := range []string{"a", "b", "a", "b"} {
+			wg.Add(1)
+			go func(agent string) {
+				defer wg.Done()
+				req := newRequest("token")
+				req.Headers(http.Header{"User-Agent": {agent}})
+				if _, err := batcher.DoRequest(req); err != nil {
+					t.Error(err)
+				}
+			}(agent)
+		}
+		wg.Wait()
+
+		sizes := rt.sizes()
+		if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 {
+			t.Errorf("expected two batches of 2 requests, got %v", sizes)
+		}
+	})
+
+	t.Run("execute context follows callers", func(t *testing.T) {
+		type ctxKey struct{}
+
+		started := make(chan context.Context, 1)
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: &waitCancelRoundTripper{started: started}}
+
+		batcher := executor.NewBatcher(exec, 10*time.Millisecond)
+		defer batcher.Close()
+
+		cancels := make([]context.CancelFunc, 2)
+		wg := sync.WaitGroup{}
+		for i := range cancels {
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+			cancels[i] = cancel
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := batcher.DoRequestCtx(ctx, newRequest("token")); err != context.Canceled {
+					t.Errorf("expected context canceled, got %v", err)
+				}
+			}()
+		}
+
+		executeCtx := <-started
+		if executeCtx.Value(ctxKey{}) != "value" {
+			t.Errorf("execute context must keep caller values")
+		}
+
+		cancels[0]()
+		select {
+		case <-executeCtx.Done():
+			t.Errorf("execute must not be canceled while other callers wait")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		cancels[1]()
+		select {
+		case <-executeCtx.Done():
+		case <-time.After(time.Second):
+			t.Errorf("execute must be canceled after all callers")
+		}
+		wg.Wait()
+	})
+}
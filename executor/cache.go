@@ -0,0 +1,32 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Кеш ответов VK API. Реализация находится в пакете cache
+type ResponseCache interface {
+	// Сообщает, кешируются ли ответы на запрос
+	Cacheable(req *request.Request) bool
+	// Возвращает сохраненный HTTP ответ на запрос. Поле Request ответа заполняет executor
+	Load(ctx context.Context, req *request.Request) (*http.Response, bool)
+	// Сохраняет тело ответа, успешно разобранного без ошибки VK API
+	Save(ctx context.Context, req *request.Request, res *http.Response, body []byte)
+}
+
+// Тело HTTP ответа, копирующее прочитанные данные для сохранения в кеш
+type recordingBody struct {
+	io.ReadCloser
+	buf bytes.Buffer
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Ответ, который умеет декодировать поле response в пользовательский тип,
+// например jsonresponseparser.JsonResponse
+type ResponseDecoder interface {
+	Decode(v any) error
+}
+
+// Выполняет запрос и декодирует поле response ответа в значение типа T.
+// Ошибки VK API возвращаются как *response.Error, ошибки декодирования - как *jsonresponseparser.DecodeError.
+//
+//	users, res, err := executor.Do[[]User](ctx, exec, req)
+func Do[T any](ctx context.Context, exec *Executor, req *request.Request) (T, response.Response, error) {
+	var result T
+
+	res, err := exec.DoRequestCtx(ctx, req)
+	if err != nil {
+		return result, res, err
+	}
+
+	decoder, ok := res.(ResponseDecoder)
+	if !ok {
+		return result, res, fmt.Errorf("response type %T does not support decoding", res)
+	}
+
+	if err := decoder.Decode(&result); err != nil {
+		return result, res, err
+	}
+
+	return result, res, nil
+}
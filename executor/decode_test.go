@@ -0,0 +1,89 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/jsonresponseparser"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+type testUser struct {
+	Id        int    `json:"id"`
+	FirstName string `json:"first_name"`
+}
+
+func TestDo(t *testing.T) {
+	newExecutor := func(body string) *executor.Executor {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				textResponse(http.StatusOK, "application/json", body),
+			},
+		}}
+		return exec
+	}
+
+	req := request.New()
+	req.Method("users.get")
+
+	t.Run("decode response", func(t *testing.T) {
+		exec := newExecutor(`{"response":[{"id":1,"first_name":"Pavel"}]}`)
+
+		users, res, err := executor.Do[[]testUser](context.Background(), exec, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res == nil {
+			t.Errorf("response is nil")
+		}
+
+		if len(users) != 1 || users[0].Id != 1 || users[0].FirstName != "Pavel" {
+			t.Errorf("unexpected users: %v", users)
+		}
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		exec := newExecutor(`{"error":{"error_code":5,"error_msg":"User authorization failed"}}`)
+
+		_, res, err := executor.Do[[]testUser](context.Background(), exec, req)
+
+		var apiError *response.Error
+		if !errors.As(err, &apiError) || apiError.IntCode() != 5 {
+			t.Errorf("expected api error, got %v", err)
+		}
+
+		if res == nil {
+			t.Errorf("response is nil")
+		}
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		exec := newExecutor(`{"response":{"id":"not a number"}}`)
+
+		_, _, err := executor.Do[testUser](context.Background(), exec, req)
+
+		var decodeError *jsonresponseparser.DecodeError
+		if !errors.As(err, &decodeError) {
+			t.Fatalf("expected decode error, got %v", err)
+		}
+
+		if decodeError.Excerpt != `{"response":{"id":"not a number"}}` {
+			t.Errorf("unexpected excerpt: %q", decodeError.Excerpt)
+		}
+	})
+
+	t.Run("response without decoder", func(t *testing.T) {
+		exec := newExecutor(`{"response":1}`)
+		exec.ResponseParser = &MessagepackParser{}
+
+		if _, _, err := executor.Do[int](context.Background(), exec, req); err == nil {
+			t.Errorf("expected unsupported response error")
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Политика обработки ошибки VK API с определенным кодом
+type ErrorCodePolicy struct {
+	Delay       time.Duration // Задержка перед повторной отправкой запроса
+	MaxAttempts int           // Максимальное количество ответов с этой ошибкой на один запрос, после которого ошибка возвращается как есть
+	PerToken    bool          // Задержка распространяется на все запросы с тем же токеном
+}
+
+// Таблица политик обработки ошибок по их коду
+type ErrorCodePolicies map[int]ErrorCodePolicy
+
+// Возвращает таблицу политик для временных ошибок VK API:
+// 1 (неизвестная ошибка), 6 (слишком много запросов в секунду), 9 (flood control) и 10 (внутренняя ошибка сервера)
+func DefaultErrorCodePolicies() ErrorCodePolicies {
+	return ErrorCodePolicies{
+		int(response.ErrorCodeUnknown):         {Delay: time.Second, MaxAttempts: 3},
+		int(response.ErrorCodeTooManyRequests): {Delay: 400 * time.Millisecond, MaxAttempts: 5},
+		int(response.ErrorCodeFloodControl):    {Delay: 10 * time.Second, MaxAttempts: 2, PerToken: true},
+		int(response.ErrorCodeInternalServer):  {Delay: time.Second, MaxAttempts: 3},
+	}
+}
+
+// Обработчик временных ошибок VK API по таблице политик.
+// Переотправляет запрос через response.Renew() при получении ошибок из таблицы, а для политик с PerToken
+// задерживает перед отправкой и остальные запросы с тем же токеном.
+// Количество попыток считается отдельно для каждого кода ошибки через executor.GetErrorCount()
+//
+//	handler := executor.NewErrorCodeHandler(executor.DefaultErrorCodePolicies())
+//	exec.RequestHook(handler.RequestHook)
+//	exec.ApiResponseHook(handler.ApiResponseHook)
+type ErrorCodeHandler struct {
+	policies ErrorCodePolicies
+	backoff  *tokenBackoff
+}
+
+// Создает обработчик временных ошибок VK API
+func NewErrorCodeHandler(policies ErrorCodePolicies) *ErrorCodeHandler {
+	return &ErrorCodeHandler{
+		policies: policies,
+		backoff: &tokenBackoff{
+			until: map[string]time.Time{},
+		},
+	}
+}
+
+// Создает хук API ответа, который переотправляет запрос через response.Renew()
+// при получении ошибок VK API из таблицы политик.
+// Задержка по токену применяется только к запросам, получившим ошибку.
+// Чтобы задерживать все запросы с токеном, используйте executor.NewErrorCodeHandler()
+//
+//	exec.ApiResponseHook(executor.NewErrorCodeHook(executor.DefaultErrorCodePolicies()))
+func NewErrorCodeHook(policies ErrorCodePolicies) ApiResponseHook {
+	return NewErrorCodeHandler(policies).ApiResponseHook
+}
+
+// Реализует executor.RequestHook: ждет окончания задержки токена запроса
+func (v *ErrorCodeHandler) RequestHook(next RequestNextHook, ctx context.Context, req *request.Request) error {
+	if token := req.GetToken(); token != "" {
+		if err := sleepCtx(ctx, v.backoff.delay(token)); err != nil {
+			return err
+		}
+	}
+
+	return next(ctx, req)
+}
+
+// Реализует executor.ApiResponseHook
+func (v *ErrorCodeHandler) ApiResponseHook(next ApiResponseNextHook, res response.Response) error {
+	var apiError *response.Error
+	if !errors.As(res.Error(), &apiError) {
+		return next(res)
+	}
+
+	policy, ok := v.policies[apiError.IntCode()]
+	if !ok {
+		return next(res)
+	}
+
+	ctx := res.Context()
+	token := GetRequest(ctx).GetToken()
+	if policy.PerToken && token != "" {
+		v.backoff.set(token, time.Now().Add(policy.Delay))
+	}
+
+	if GetErrorCount(ctx, apiError.IntCode()) >= policy.MaxAttempts {
+		return next(res)
+	}
+
+	delay := policy.Delay
+	if tokenDelay := v.backoff.delay(token); token != "" && tokenDelay > delay {
+		delay = tokenDelay
+	}
+
+	if err := sleepCtx(ctx, delay); err != nil {
+		return err
+	}
+
+	res.Renew(true)
+	return next(res)
+}
+
+// Хранит время окончания задержки запросов по токенам
+type tokenBackoff struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// Продлевает задержку для токена
+func (v *tokenBackoff) set(token string, until time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if until.After(v.until[token]) {
+		v.until[token] = until
+	}
+}
+
+// Возвращает оставшуюся задержку для токена
+func (v *tokenBackoff) delay(token string) time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	until, ok := v.until[token]
+	if !ok {
+		return 0
+	}
+
+	delay := time.Until(until)
+	if delay <= 0 {
+		delete(v.until, token)
+		return 0
+	}
+
+	return delay
+}
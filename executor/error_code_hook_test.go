@@ -0,0 +1,171 @@
+package executor_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+func TestErrorCodeHook(t *testing.T) {
+	okResponse := textResponse(http.StatusOK, "application/json", `{"response":1}`)
+	tooManyRequests := textResponse(http.StatusOK, "application/json", `{"error":{"error_code":6,"error_msg":"Too many requests per second"}}`)
+
+	newRequest := func() *request.Request {
+		req := request.New()
+		req.Method("users.get")
+		req.GetParams().AccessToken("token")
+		return req
+	}
+
+	policies := executor.ErrorCodePolicies{
+		6: {Delay: time.Millisecond, MaxAttempts: 3},
+		9: {Delay: 50 * time.Millisecond, MaxAttempts: 2, PerToken: true},
+	}
+
+	t.Run("renew request on transient error", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){tooManyRequests, tooManyRequests, okResponse},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.ApiResponseHook(executor.NewErrorCodeHook(policies))
+
+		res, err := exec.DoRequest(newRequest())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != `{"response":1}` {
+			t.Errorf("unexpected response: %s", res)
+		}
+
+		if len(rt.attempts) != 3 {
+			t.Errorf("expected 3 attempts, got %d", len(rt.attempts))
+		}
+	})
+
+	t.Run("return error after max attempts", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){tooManyRequests},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.ApiResponseHook(executor.NewErrorCodeHook(policies))
+
+		_, err := exec.DoRequest(newRequest())
+
+		var apiError *response.Error
+		if !errors.As(err, &apiError) || apiError.IntCode() != 6 {
+			t.Errorf("expected api error with code 6, got %v", err)
+		}
+
+		if len(rt.attempts) != 3 {
+			t.Errorf("expected 3 attempts, got %d", len(rt.attempts))
+		}
+	})
+
+	t.Run("flood control delays token", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				textResponse(http.StatusOK, "application/json", `{"error":{"error_code":9,"error_msg":"Flood control"}}`),
+				okResponse,
+			},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.ApiResponseHook(executor.NewErrorCodeHook(policies))
+
+		start := time.Now()
+		if _, err := exec.DoRequest(newRequest()); err != nil {
+			t.Fatal(err)
+		}
+
+		if time.Since(start) < 50*time.Millisecond {
+			t.Errorf("request renewed without flood control delay")
+		}
+	})
+
+	t.Run("flood control delays other requests with token", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				textResponse(http.StatusOK, "application/json", `{"error":{"error_code":9,"error_msg":"Flood control"}}`),
+				okResponse,
+			},
+		}
+
+		handler := executor.NewErrorCodeHandler(executor.ErrorCodePolicies{
+			9: {Delay: 50 * time.Millisecond, MaxAttempts: 1, PerToken: true},
+		})
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.RequestHook(handler.RequestHook)
+		exec.ApiResponseHook(handler.ApiResponseHook)
+
+		start := time.Now()
+		if _, err := exec.DoRequest(newRequest()); !errors.Is(err, response.ErrFloodControl) {
+			t.Fatalf("expected flood control error, got %v", err)
+		}
+
+		if _, err := exec.DoRequest(newRequest()); err != nil {
+			t.Fatal(err)
+		}
+
+		if time.Since(start) < 50*time.Millisecond {
+			t.Errorf("request with the same token sent without flood control delay")
+		}
+	})
+
+	t.Run("count attempts per error code", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){networkError, tooManyRequests},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		retryPolicy := executor.NewBackoffRetryPolicy(2)
+		retryPolicy.BaseDelay = time.Millisecond
+		exec.RetryPolicy = retryPolicy
+		exec.ApiResponseHook(executor.NewErrorCodeHook(policies))
+
+		_, err := exec.DoRequest(newRequest())
+		if !errors.Is(err, response.ErrTooManyRequests) {
+			t.Errorf("expected too many requests error, got %v", err)
+		}
+
+		if len(rt.attempts) != 4 {
+			t.Errorf("expected network retry and 3 attempts with error 6, got %d attempts", len(rt.attempts))
+		}
+	})
+
+	t.Run("renew limit", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){okResponse},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.MaxRenews = 2
+		exec.ApiResponseHook(func(next executor.ApiResponseNextHook, res response.Response) error {
+			res.Renew(true)
+			return next(res)
+		})
+
+		_, err := exec.DoRequest(newRequest())
+		if !errors.Is(err, executor.ErrRenewLimitExceeded) {
+			t.Errorf("expected renew limit error, got %v", err)
+		}
+
+		if len(rt.attempts) != 3 {
+			t.Errorf("expected 3 attempts, got %d", len(rt.attempts))
+		}
+	})
+}
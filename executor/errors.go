@@ -0,0 +1,10 @@
+package executor
+
+import "errors"
+
+// Возвращается, если запрос был переотправлен через response.Renew() больше, чем разрешено executor.MaxRenews
+var ErrRenewLimitExceeded = errors.New("request renew limit exceeded")
+
+// Ошибка, после которой HTTP запрос не повторяется политикой executor.RetryPolicy и не переключает хост пула.
+// Транспорт может обернуть ее, чтобы отказать в запросе без обращения к серверу, например при сработавшем предохранителе
+var ErrNotRetryable = errors.New("request is not retryable")
@@ -2,19 +2,29 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/ciricc/vkapiexecutor/jsonresponseparser"
 	"github.com/ciricc/vkapiexecutor/request"
 	"github.com/ciricc/vkapiexecutor/response"
 	"github.com/ciricc/vkapiexecutor/responseparser"
+	"github.com/ciricc/vkapiexecutor/tracing"
 )
 
 var (
 	DefaultResponseParser responseparser.Parser = &jsonresponseparser.JsonResponseParser{}
+	// Максимальное количество переотправок запроса через response.Renew() по умолчанию
+	DefaultMaxRenews = 10
 )
 
+type RequestNextHook func(ctx context.Context, req *request.Request) error
+
+type RequestHook func(next RequestNextHook, ctx context.Context, req *request.Request) error
+
 type ApiResponseNextHook func(res response.Response) error
 
 type ApiResponseHook func(next ApiResponseNextHook, res response.Response) error
@@ -23,6 +33,9 @@ type HttpResponseNextHook func(res *http.Response) error
 
 type HttpResponseHook func(next HttpResponseNextHook, res *http.Response) error
 
+// Обработчик завершения запроса. res равен nil, если ответ VK API не получен
+type RequestDoneHook func(ctx context.Context, req *request.Request, res response.Response, err error)
+
 // Отвечает за выполнение API запросов ВКонтакте
 type Executor struct {
 	// HTTP клиент для отправки запросов.
@@ -30,44 +43,126 @@ type Executor struct {
 	HttpClient *http.Client
 	// Парсер ответа ВКонтакте. Можно переназначить для парсинга других форматов
 	ResponseParser responseparser.Parser
+	// Политика повторной отправки запроса при сетевых ошибках и ошибках сервера.
+	// По умолчанию nil - запрос отправляется один раз
+	RetryPolicy RetryPolicy
+	// Максимальное количество переотправок одного запроса через response.Renew().
+	// После превышения возвращается ошибка executor.ErrRenewLimitExceeded. Значение 0 отключает ограничение
+	MaxRenews int
+	// Источник токенов доступа. Если задан, executor выбирает токен для каждой попытки,
+	// подставляя его в копию запроса, и переотправляет запрос с другим токеном, если источник этого требует.
+	// Смена токена считается переотправкой и учитывается в MaxRenews
+	Tokens TokenProvider
+	// Пул хостов VK API. Если задан, запросы отправляются на хосты пула вместо request.GetBaseUrl()
+	// с переключением на следующий хост при сетевых ошибках и ответах 5xx
+	Hosts *HostPool
+	// Объединитель одинаковых одновременных запросов. Если задан, одинаковые запросы к разрешенным методам
+	// выполняются одним HTTP запросом, а каждый вызов получает свой ответ
+	Singleflight *Singleflight
+	// Кеш ответов. Если задан, ответы на кешируемые запросы берутся из кеша
+	// и проходят через хуки и парсер так же, как ответы сервера
+	Cache ResponseCache
+	// Получатель метрик запросов
+	Metrics Metrics
+	// Трассировщик запросов. Если задан, executor создает span'ы для запроса, каждой попытки,
+	// HTTP запроса, разбора ответа и каждого хука, а лимитер - для ожидания
+	Tracer tracing.Tracer
 
+	// Последний добавленный обработчик исходящего запроса
+	requestHook RequestHook
 	// Последний добавленный обработчик API ответа
 	apiResponseHook ApiResponseHook
 	// Последний добавленный обработчик HTTP ответа
 	httpResponseHook HttpResponseHook
+	// Обработчики завершения запроса
+	requestDoneHook RequestDoneHook
 }
 
 func New() *Executor {
 	return &Executor{
 		HttpClient:      http.DefaultClient,
 		ResponseParser:  DefaultResponseParser,
+		MaxRenews:       DefaultMaxRenews,
+		requestHook:     func(next RequestNextHook, ctx context.Context, req *request.Request) error { return nil },
 		apiResponseHook: func(next ApiResponseNextHook, res response.Response) error { return nil },
 		httpResponseHook: func(next HttpResponseNextHook, res *http.Response) error {
 			return nil
 		},
+		requestDoneHook: func(ctx context.Context, req *request.Request, res response.Response, err error) {},
+	}
+}
+
+// Устанавливает хук для обработки запроса перед отправкой HTTP запроса.
+// Хук вызывается перед каждой попыткой и может изменить запрос (добавить параметры, подпись, заголовки)
+// или прервать его выполнение, вернув ошибку.
+// Хук получает копию запроса, созданную для одного вызова executor'а: изменения сохраняются между попытками
+// этого вызова и видны через executor.GetRequest(ctx), но не затрагивают переданный запрос
+func (v *Executor) RequestHook(hook RequestHook) {
+	nextHook := v.requestHook
+	name := hookName(hook)
+	v.requestHook = func(next RequestNextHook, ctx context.Context, req *request.Request) error {
+		ctx, span := tracing.Start(ctx, "vkapi.hook.request", tracing.String("vkapi.hook", name))
+		defer span.End()
+
+		err := hook(func(ctx context.Context, req *request.Request) error {
+			return nextHook(nil, ctx, req)
+		}, ctx, req)
+		span.RecordError(err)
+
+		return err
 	}
 }
 
 // Устанавливает хук для обработки ответа VK API
 func (v *Executor) ApiResponseHook(hook ApiResponseHook) {
 	nextHook := v.apiResponseHook
+	name := hookName(hook)
 	v.apiResponseHook = func(next ApiResponseNextHook, res response.Response) error {
-		return hook(func(res response.Response) error {
+		_, span := tracing.Start(res.Context(), "vkapi.hook.api_response", tracing.String("vkapi.hook", name))
+		defer span.End()
+
+		err := hook(func(res response.Response) error {
 			return nextHook(nil, res)
 		}, res)
+		span.RecordError(err)
+
+		return err
 	}
 }
 
 // Устанавливает обработчик ответов сервера
 func (v *Executor) HttpResponseHook(hook HttpResponseHook) {
 	nextHook := v.httpResponseHook
+	name := hookName(hook)
 	v.httpResponseHook = func(next HttpResponseNextHook, res *http.Response) error {
-		return hook(func(res *http.Response) error {
+		_, span := tracing.Start(httpResponseContext(res), "vkapi.hook.http_response", tracing.String("vkapi.hook", name))
+		defer span.End()
+
+		err := hook(func(res *http.Response) error {
 			return nextHook(nil, res)
 		}, res)
+		span.RecordError(err)
+
+		return err
+	}
+}
+
+// Добавляет обработчик завершения запроса.
+// Обработчик вызывается один раз после всех попыток, в том числе если запрос завершился сетевой ошибкой
+// или ошибкой разбора ответа и хуки API ответа не вызывались. Обработчики вызываются в порядке добавления
+func (v *Executor) RequestDoneHook(hook RequestDoneHook) {
+	prevHook := v.requestDoneHook
+	v.requestDoneHook = func(ctx context.Context, req *request.Request, res response.Response, err error) {
+		prevHook(ctx, req, res, err)
+		hook(ctx, req, res, err)
 	}
 }
 
+// Отчищает очередь из обработчиков исходящих запросов
+func (v *Executor) ResetRequestHandlers() {
+	v.requestHook = func(next RequestNextHook, ctx context.Context, req *request.Request) error { return nil }
+}
+
 // Отчищает очередь из middleware API ответов
 func (v *Executor) ResetApiResponseHandlers() {
 	v.apiResponseHook = func(next ApiResponseNextHook, res response.Response) error { return nil }
@@ -78,6 +173,11 @@ func (v *Executor) ResetHttpResponseHandlers() {
 	v.httpResponseHook = func(next HttpResponseNextHook, res *http.Response) error { return nil }
 }
 
+// Отчищает очередь из обработчиков завершения запроса
+func (v *Executor) ResetRequestDoneHandlers() {
+	v.requestDoneHook = func(ctx context.Context, req *request.Request, res response.Response, err error) {}
+}
+
 // Выполняет запрос к VK API
 // Используйте executor.DoRequestCtx(), если есть задача контролировать таймаут и контекст запроса
 func (v *Executor) DoRequest(req *request.Request) (response.Response, error) {
@@ -94,7 +194,7 @@ func (v *Executor) DoRequestCtx(ctx context.Context, req *request.Request) (resp
 // Вы можете задать свой собственный парсер ответа. Например, ВКонтакте поддерживает формат messagepack (users.get.msgpack)
 // Возвращает ответ VK API. В случае, если возникла ошибка выоплнения HTTP запроса, то будет response.Response == nil.
 // Если возникла ошибка при вызове метода API, вернется полный ответ сервера и информация об ошибке типа response.Error
-func (v *Executor) DoRequestCtxParser(ctx context.Context, req *request.Request, parser responseparser.Parser) (response.Response, error) {
+func (v *Executor) DoRequestCtxParser(ctx context.Context, req *request.Request, parser responseparser.Parser) (res response.Response, err error) {
 	if req == nil {
 		return nil, fmt.Errorf("input request empty")
 	}
@@ -103,40 +203,270 @@ func (v *Executor) DoRequestCtxParser(ctx context.Context, req *request.Request,
 		return nil, fmt.Errorf("response parser is nil")
 	}
 
-	ctx = context.WithValue(ctx, requestContextKeyVal, req)
+	if v.Metrics != nil {
+		start := time.Now()
+		defer func() {
+			v.Metrics.ObserveRequest(req.GetMethod(), TokenId(req), time.Since(start), err)
+		}()
+	}
 
-	httpReq, err := req.HttpRequestPost()
-	if err != nil {
-		return nil, err
+	if v.Tracer != nil {
+		ctx = tracing.ContextWithTracer(ctx, v.Tracer)
 	}
 
-	httpReq = httpReq.WithContext(ctx)
+	ctx, span := tracing.Start(ctx, "vkapi.request", tracing.String("vkapi.method", req.GetMethod()))
+	defer func() {
+		span.SetAttributes(tracing.String("vkapi.token_id", TokenId(req)))
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Хуки и источник токенов изменяют копию запроса, чтобы не изменять общий шаблон
+	req = req.Clone()
+	if v.Tokens != nil && req.GetParams() == nil {
+		req.Params(request.NewParams())
+	}
+
+	ctx = context.WithValue(ctx, requestContextKeyVal, req)
+
+	// Счетчики заполняются и читаются хуками в горутине запроса, поэтому блокировка не нужна
+	errorCounts := map[int]int{}
+	ctx = context.WithValue(ctx, errorCountsContextKeyVal, errorCounts)
+
+	// Обработчики завершения получают контекст последней попытки
+	doneCtx := ctx
+	defer func() {
+		v.requestDoneHook(doneCtx, req, res, err)
+	}()
+
+	renews := 0
+	reason := ""
+	var attemptSpan tracing.Span
+	defer func() {
+		if attemptSpan != nil {
+			attemptSpan.End()
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		if attemptSpan != nil {
+			attemptSpan.End()
+		}
+
+		attemptCtx := context.WithValue(ctx, requestTryContextKeyVal, attempt)
+		attemptCtx = context.WithValue(attemptCtx, attemptStartContextKeyVal, time.Now())
+		attemptCtx, attemptSpan = tracing.Start(attemptCtx, "vkapi.attempt", tracing.Int("vkapi.attempt", attempt))
+		if reason != "" {
+			attemptSpan.SetAttributes(tracing.String("vkapi.attempt.reason", reason))
+		}
+		doneCtx = attemptCtx
+
+		token := ""
+		if v.Tokens != nil {
+			var err error
+			token, err = v.Tokens.Token(attemptCtx, req)
+			if err != nil {
+				return nil, err
+			}
+			req.GetParams().AccessToken(token)
+		}
+
+		err := v.requestHook(nil, attemptCtx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		httpReq, err := req.HttpRequestPost()
+		if err != nil {
+			return nil, err
+		}
+
+		var recorder *recordingBody
+		res, cached := v.loadCachedResponse(attemptCtx, req)
+		if cached {
+			res.Request = httpReq.WithContext(attemptCtx)
+			attemptSpan.SetAttributes(tracing.Bool("vkapi.cache_hit", true))
+		} else {
+			res, err = v.sendTracedHttpRequest(attemptCtx, req, httpReq, parser)
 
-	res, err := v.HttpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("http error: %w", err)
+			if v.RetryPolicy != nil {
+				if delay, retry := v.RetryPolicy.Retry(attemptCtx, res, err); retry {
+					if res != nil {
+						res.Body.Close()
+					}
+
+					if err := sleepCtx(ctx, delay); err != nil {
+						return nil, err
+					}
+
+					reason = "retry"
+					v.observeRetry(req, reason)
+					continue
+				}
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("http error: %w", err)
+			}
+
+			if v.Cache != nil && v.Cache.Cacheable(req) && !isStreamParser(parser) {
+				recorder = &recordingBody{ReadCloser: res.Body}
+				res.Body = recorder
+			}
+		}
+
+		err = v.httpResponseHook(nil, res)
+		if err != nil {
+			return nil, err
+		}
+
+		_, parseSpan := tracing.Start(attemptCtx, "vkapi.parse")
+		apiResponse, err := parser.Parse(res)
+		res.Body.Close()
+		parseSpan.RecordError(err)
+		parseSpan.End()
+
+		if err != nil {
+			return nil, fmt.Errorf("parse response error: %w", err)
+		}
+
+		if code := response.CodeOf(apiResponse.Error()); code != 0 {
+			errorCounts[int(code)]++
+		}
+		v.observeApiError(req, apiResponse)
+
+		err = v.apiResponseHook(nil, apiResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		if recorder != nil && apiResponse.Error() == nil && !apiResponse.IsRenew() {
+			v.Cache.Save(attemptCtx, req, res, recorder.buf.Bytes())
+		}
+
+		renew := apiResponse.IsRenew()
+		if v.Tokens != nil && v.Tokens.Release(attemptCtx, token, apiResponse.Error()) {
+			renew = true
+		}
+
+		if renew {
+			renews++
+			if v.MaxRenews > 0 && renews > v.MaxRenews {
+				if apiErr := apiResponse.Error(); apiErr != nil {
+					return apiResponse, fmt.Errorf("%w: %w", ErrRenewLimitExceeded, apiErr)
+				}
+				return apiResponse, ErrRenewLimitExceeded
+			}
+
+			reason = "renew"
+			v.observeRetry(req, reason)
+			continue
+		}
+
+		return apiResponse, apiResponse.Error()
+	}
+}
+
+// Возвращает ответ из кеша, если он задан
+func (v *Executor) loadCachedResponse(ctx context.Context, req *request.Request) (*http.Response, bool) {
+	if v.Cache == nil || !v.Cache.Cacheable(req) {
+		return nil, false
 	}
+	return v.Cache.Load(ctx, req)
+}
+
+// Отправляет HTTP запрос внутри span'а vkapi.http.
+// Контекст ответа заменяется контекстом попытки, чтобы span'ы хуков и парсера относились к попытке
+func (v *Executor) sendTracedHttpRequest(ctx context.Context, req *request.Request, httpReq *http.Request, parser responseparser.Parser) (*http.Response, error) {
+	httpCtx, span := tracing.Start(ctx, "vkapi.http")
+	defer span.End()
+
+	res, err := v.sendSharedHttpRequest(httpCtx, req, httpReq, parser)
+	span.RecordError(err)
 
-	err = v.httpResponseHook(nil, res)
-	if err != nil {
-		return nil, err
+	if res != nil {
+		span.SetAttributes(tracing.Int("http.status_code", res.StatusCode))
+		if res.Request != nil && httpCtx != ctx {
+			res.Request = res.Request.WithContext(ctx)
+		}
 	}
 
-	apiResponse, err := parser.Parse(res)
-	res.Body.Close()
+	return res, err
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("parse response error: %w", err)
+// Отправляет HTTP запрос, объединяя его с такими же выполняющимися запросами, если задан executor.Singleflight.
+// Ответы для потокового парсера не объединяются, чтобы не читать их тело в память
+func (v *Executor) sendSharedHttpRequest(ctx context.Context, req *request.Request, httpReq *http.Request, parser responseparser.Parser) (*http.Response, error) {
+	if v.Singleflight == nil || !v.Singleflight.Allowed(req) || isStreamParser(parser) {
+		return v.sendHttpRequest(ctx, req, httpReq)
 	}
 
-	err = v.apiResponseHook(nil, apiResponse)
-	if err != nil {
-		return nil, err
+	return v.Singleflight.do(ctx, req, httpReq, bodyLimit(parser), func() (*http.Response, error) {
+		return v.sendHttpRequest(ctx, req, httpReq)
+	})
+}
+
+// Отправляет HTTP запрос одной попытки выполнения API запроса.
+// Если задан пул хостов, перебирает хосты до первого ответа без ошибки сервера
+func (v *Executor) sendHttpRequest(ctx context.Context, req *request.Request, httpReq *http.Request) (*http.Response, error) {
+	if v.Hosts == nil {
+		return v.HttpClient.Do(httpReq.WithContext(ctx))
 	}
 
-	if apiResponse.IsRenew() {
-		return v.DoRequestCtxParser(ctx, req, parser)
+	var res *http.Response
+	var err error
+
+	hosts := v.Hosts.Hosts()
+	for i, baseUrl := range hosts {
+		if i > 0 {
+			// Тело предыдущего запроса уже прочитано, поэтому запрос собирается заново
+			httpReq, err = req.HttpRequestPost()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		hostUrl, urlErr := request.MethodUrl(baseUrl, req.GetMethod())
+		if urlErr != nil {
+			return nil, urlErr
+		}
+
+		hostReq := httpReq.WithContext(ctx)
+		hostReq.URL = hostUrl
+
+		res, err = v.HttpClient.Do(hostReq)
+		if errors.Is(err, ErrNotRetryable) {
+			return nil, err
+		}
+
+		if !isHostFailure(res, err) {
+			v.Hosts.MarkSuccess(baseUrl)
+			return res, nil
+		}
+
+		if ctx.Err() != nil {
+			return res, err
+		}
+
+		v.Hosts.MarkFailure(baseUrl)
+
+		if res != nil && i < len(hosts)-1 {
+			res.Body.Close()
+		}
 	}
 
-	return apiResponse, apiResponse.Error()
+	return res, err
+}
+
+// Выполняет запрос к VK API и копирует тело ответа в w, не сохраняя его в памяти.
+// Ответ разбирается потоково через jsonresponseparser.StreamParser: ошибки VK API возвращаются так же,
+// как в DoRequestCtx(), а Body() ответа пустой.
+// Если хук запросит переотправку запроса через Renew(), в w будет записано тело каждого ответа.
+// Ответы не сохраняются в executor.Cache, чтобы не буферизировать тело в памяти
+func (v *Executor) DoRequestTo(ctx context.Context, req *request.Request, w io.Writer) (response.Response, error) {
+	parser := jsonresponseparser.NewStreamParser(w)
+	if jsonParser, ok := v.ResponseParser.(*jsonresponseparser.JsonResponseParser); ok {
+		parser.MaxBodySize = jsonParser.MaxBodySize
+	}
+	return v.DoRequestCtxParser(ctx, req, parser)
 }
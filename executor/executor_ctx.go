@@ -6,7 +6,15 @@ type requestTryContextKey struct{}
 // Ключ запроса в контексте
 type requestContextKey struct{}
 
+// Ключ времени начала попытки в контексте
+type attemptStartContextKey struct{}
+
+// Ключ счетчиков ошибок VK API запроса в контексте
+type errorCountsContextKey struct{}
+
 var (
-	requestContextKeyVal    = requestContextKey{}
-	requestTryContextKeyVal = requestTryContextKey{}
+	requestContextKeyVal      = requestContextKey{}
+	requestTryContextKeyVal   = requestTryContextKey{}
+	attemptStartContextKeyVal = attemptStartContextKey{}
+	errorCountsContextKeyVal  = errorCountsContextKey{}
 )
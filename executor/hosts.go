@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Время, на которое хост исключается из перебора после ошибки, по умолчанию
+var DefaultHostFailureTimeout = 30 * time.Second
+
+// Пул хостов VK API с отслеживанием их доступности.
+// Executor перебирает хосты по порядку, начиная с доступных,
+// и переключается на следующий хост при сетевых ошибках и ответах 5xx
+type HostPool struct {
+	// Время, на которое хост считается недоступным после ошибки.
+	// При каждой следующей ошибке подряд время увеличивается
+	FailureTimeout time.Duration
+
+	mu    sync.Mutex
+	hosts []*hostState
+}
+
+// Состояние хоста
+type hostState struct {
+	baseUrl   string    // URL путь к VK API на этом хосте
+	failures  int       // Количество ошибок подряд
+	downUntil time.Time // Время, до которого хост считается недоступным
+}
+
+// Создает пул хостов из списка URL путей к VK API в порядке приоритета.
+//
+//	executor.NewHostPool("https://api.vk.com/method/", "https://api.vk.ru/method/")
+func NewHostPool(baseUrls ...string) (*HostPool, error) {
+	if len(baseUrls) == 0 {
+		return nil, errors.New("host pool is empty")
+	}
+
+	hosts := make([]*hostState, len(baseUrls))
+	for i, baseUrl := range baseUrls {
+		if _, err := url.Parse(baseUrl); err != nil {
+			return nil, fmt.Errorf("parse host url error: %w", err)
+		}
+		hosts[i] = &hostState{baseUrl: baseUrl}
+	}
+
+	return &HostPool{
+		FailureTimeout: DefaultHostFailureTimeout,
+		hosts:          hosts,
+	}, nil
+}
+
+// Возвращает URL пути хостов в порядке перебора:
+// сначала доступные в порядке приоритета, затем недоступные в порядке их скорого восстановления
+func (v *HostPool) Hosts() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	hosts := make([]*hostState, len(v.hosts))
+	copy(hosts, v.hosts)
+
+	sort.SliceStable(hosts, func(i, j int) bool {
+		iDown, jDown := hosts[i].downUntil.After(now), hosts[j].downUntil.After(now)
+		if iDown != jDown {
+			return !iDown
+		}
+		return iDown && hosts[i].downUntil.Before(hosts[j].downUntil)
+	})
+
+	baseUrls := make([]string, len(hosts))
+	for i, host := range hosts {
+		baseUrls[i] = host.baseUrl
+	}
+
+	return baseUrls
+}
+
+// Сообщает, доступен ли хост
+func (v *HostPool) IsHealthy(baseUrl string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	host := v.host(baseUrl)
+	return host == nil || !host.downUntil.After(time.Now())
+}
+
+// Отмечает ошибку запроса к хосту и исключает его из перебора на время FailureTimeout
+func (v *HostPool) MarkFailure(baseUrl string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	host := v.host(baseUrl)
+	if host == nil {
+		return
+	}
+
+	host.failures++
+
+	timeout := v.FailureTimeout
+	for i := 1; i < host.failures && i < 6; i++ {
+		timeout *= 2
+	}
+	host.downUntil = time.Now().Add(timeout)
+}
+
+// Отмечает успешный запрос к хосту
+func (v *HostPool) MarkSuccess(baseUrl string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if host := v.host(baseUrl); host != nil {
+		host.failures = 0
+		host.downUntil = time.Time{}
+	}
+}
+
+// Возвращает состояние хоста по его URL пути
+func (v *HostPool) host(baseUrl string) *hostState {
+	for _, host := range v.hosts {
+		if host.baseUrl == baseUrl {
+			return host
+		}
+	}
+	return nil
+}
+
+// Сообщает, нужно ли переключиться на другой хост после ответа
+func isHostFailure(res *http.Response, err error) bool {
+	return err != nil || res.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,115 @@
+package executor_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+func TestHostPool(t *testing.T) {
+	newApiServer := func(status int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("user_ids") != "1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+	}
+
+	newRequest := func() *request.Request {
+		req := request.New()
+		req.Method("users.get")
+		req.GetParams().Set("user_ids", "1")
+		return req
+	}
+
+	t.Run("request base url", func(t *testing.T) {
+		server := newApiServer(http.StatusOK, `{"response":1}`)
+		defer server.Close()
+
+		req := newRequest()
+		req.BaseUrl(server.URL + "/method/")
+
+		res, err := executor.New().DoRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.HttpResponse().Request.URL.String() != server.URL+"/method/users.get" {
+			t.Errorf("unexpected request url: %s", res.HttpResponse().Request.URL)
+		}
+
+		if request.New().GetBaseUrl() != request.DefaultBaseRequestUrl {
+			t.Errorf("default base url not used")
+		}
+	})
+
+	t.Run("failover to next host", func(t *testing.T) {
+		badGateway := newApiServer(http.StatusBadGateway, "<html>502 Bad Gateway</html>")
+		defer badGateway.Close()
+
+		closed := newApiServer(http.StatusOK, `{"response":0}`)
+		closed.Close()
+
+		healthy := newApiServer(http.StatusOK, `{"response":1}`)
+		defer healthy.Close()
+
+		hosts, err := executor.NewHostPool(closed.URL+"/method/", badGateway.URL+"/method/", healthy.URL+"/method/")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exec := executor.New()
+		exec.Hosts = hosts
+
+		res, err := exec.DoRequest(newRequest())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != `{"response":1}` {
+			t.Errorf("unexpected response: %s", res)
+		}
+
+		if hosts.IsHealthy(closed.URL+"/method/") || hosts.IsHealthy(badGateway.URL+"/method/") {
+			t.Errorf("failed hosts are healthy")
+		}
+
+		if !hosts.IsHealthy(healthy.URL + "/method/") {
+			t.Errorf("healthy host marked as failed")
+		}
+
+		if hosts.Hosts()[0] != healthy.URL+"/method/" {
+			t.Errorf("healthy host is not first: %v", hosts.Hosts())
+		}
+	})
+
+	t.Run("all hosts failed", func(t *testing.T) {
+		closed := newApiServer(http.StatusOK, `{"response":0}`)
+		closed.Close()
+
+		hosts, err := executor.NewHostPool(closed.URL + "/method/")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exec := executor.New()
+		exec.Hosts = hosts
+
+		if _, err := exec.DoRequest(newRequest()); err == nil {
+			t.Errorf("expected http error")
+		}
+	})
+
+	t.Run("empty host pool", func(t *testing.T) {
+		if _, err := executor.NewHostPool(); err == nil {
+			t.Errorf("expected empty pool error")
+		}
+	})
+}
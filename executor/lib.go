@@ -2,8 +2,14 @@ package executor
 
 import (
 	"context"
+	"net/http"
+	"reflect"
+	"runtime"
+	"time"
 
+	"github.com/ciricc/vkapiexecutor/jsonresponseparser"
 	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/responseparser"
 )
 
 // Возвращает объект запроса из контекста
@@ -15,3 +21,70 @@ func GetRequest(ctx context.Context) *request.Request {
 	}
 	return nil
 }
+
+// Возвращает номер текущей попытки выполнения запроса, начиная с 1.
+// Учитываются как повторы по политике executor.RetryPolicy, так и переотправки через response.Renew().
+// Если контекст не относится к запросу executor'а, возвращает 0
+func GetAttempt(ctx context.Context) int {
+	if ctx != nil {
+		if attempt, ok := ctx.Value(requestTryContextKeyVal).(int); ok {
+			return attempt
+		}
+	}
+	return 0
+}
+
+// Возвращает время начала текущей попытки выполнения запроса.
+// Если контекст не относится к запросу executor'а, возвращает нулевое время
+func GetAttemptStart(ctx context.Context) time.Time {
+	if ctx != nil {
+		if start, ok := ctx.Value(attemptStartContextKeyVal).(time.Time); ok {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// Возвращает количество ответов с ошибкой VK API code, полученных при выполнении запроса, включая текущий ответ.
+// В отличие от executor.GetAttempt() не учитывает повторы после сетевых ошибок и переотправки из-за других ошибок.
+// Ошибки вызовов внутри execute не учитываются.
+// Если контекст не относится к запросу executor'а, возвращает 0
+func GetErrorCount(ctx context.Context, code int) int {
+	if ctx != nil {
+		if counts, ok := ctx.Value(errorCountsContextKeyVal).(map[int]int); ok {
+			return counts[code]
+		}
+	}
+	return 0
+}
+
+// Возвращает имя функции хука для span'ов трассировки
+func hookName(hook any) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(hook).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// Возвращает контекст запроса HTTP ответа
+func httpResponseContext(res *http.Response) context.Context {
+	if res != nil && res.Request != nil {
+		return res.Request.Context()
+	}
+	return context.Background()
+}
+
+// Сообщает, разбирается ли ответ потоково. Тела таких ответов не читаются в память
+// для кеша и объединения запросов
+func isStreamParser(parser responseparser.Parser) bool {
+	_, ok := parser.(*jsonresponseparser.StreamParser)
+	return ok
+}
+
+// Возвращает ограничение размера тела ответа парсера или 0, если ограничения нет
+func bodyLimit(parser responseparser.Parser) int64 {
+	if jsonParser, ok := parser.(*jsonresponseparser.JsonResponseParser); ok {
+		return jsonParser.MaxBodySize
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Получатель метрик executor'а. Адаптер для Prometheus находится в пакете metrics.
+// Метки method и tokenId - имя метода и хеш токена запроса (см. executor.TokenId)
+type Metrics interface {
+	// Вызывается после завершения запроса со всеми его попытками
+	ObserveRequest(method, tokenId string, duration time.Duration, err error)
+	// Вызывается для каждого ответа с ошибкой VK API, в том числе для ответов, после которых запрос переотправляется
+	ObserveApiError(method, tokenId string, code int)
+	// Вызывается перед повторной отправкой запроса.
+	// reason - "retry" для повтора по executor.RetryPolicy или "renew" для переотправки после ответа VK API
+	ObserveRetry(method, tokenId, reason string)
+}
+
+// Возвращает короткий идентификатор токена запроса для меток метрик и логов, не раскрывающий сам токен.
+// Если токена нет, возвращает пустую строку
+func TokenId(req *request.Request) string {
+	token := req.GetToken()
+	if token == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:6])
+}
+
+// Передает ошибку VK API ответа в метрики
+func (v *Executor) observeApiError(req *request.Request, res response.Response) {
+	var apiErr *response.Error
+	if v.Metrics != nil && errors.As(res.Error(), &apiErr) {
+		v.Metrics.ObserveApiError(req.GetMethod(), TokenId(req), apiErr.IntCode())
+	}
+}
+
+// Передает повтор запроса в метрики
+func (v *Executor) observeRetry(req *request.Request, reason string) {
+	if v.Metrics != nil {
+		v.Metrics.ObserveRetry(req.GetMethod(), TokenId(req), reason)
+	}
+}
@@ -0,0 +1,160 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+func TestRequestHook(t *testing.T) {
+	newRequest := func() *request.Request {
+		req := request.New()
+		req.Method("users.get")
+		return req
+	}
+
+	t.Run("change request before sending", func(t *testing.T) {
+		var sentForm string
+		var sentHeader string
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				func(req *http.Request) (*http.Response, error) {
+					req.ParseForm()
+					sentForm = req.PostForm.Encode()
+					sentHeader = req.Header.Get("X-Trace-Id")
+					return textResponse(http.StatusOK, "application/json", `{"response":1}`)(req)
+				},
+			},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		var order []string
+		exec.RequestHook(func(next executor.RequestNextHook, ctx context.Context, req *request.Request) error {
+			order = append(order, "params")
+			req.GetParams().Set("user_ids", "1")
+			return next(ctx, req)
+		})
+		exec.RequestHook(func(next executor.RequestNextHook, ctx context.Context, req *request.Request) error {
+			order = append(order, "headers")
+			if executor.GetRequest(ctx) != req || executor.GetAttempt(ctx) != 1 {
+				t.Errorf("request context not passed to hook")
+			}
+			req.AppendHeaders(http.Header{"X-Trace-Id": {"trace"}})
+			return next(ctx, req)
+		})
+
+		if _, err := exec.DoRequest(newRequest()); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(sentForm, "user_ids=1") {
+			t.Errorf("param from hook not sent: %q", sentForm)
+		}
+
+		if sentHeader != "trace" {
+			t.Errorf("header from hook not sent: %q", sentHeader)
+		}
+
+		if strings.Join(order, ",") != "headers,params" {
+			t.Errorf("unexpected hooks order: %v", order)
+		}
+	})
+
+	t.Run("abort request", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				textResponse(http.StatusOK, "application/json", `{"response":1}`),
+			},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		abortErr := errors.New("request rejected")
+		exec.RequestHook(func(next executor.RequestNextHook, ctx context.Context, req *request.Request) error {
+			return abortErr
+		})
+
+		if _, err := exec.DoRequest(newRequest()); err != abortErr {
+			t.Errorf("expected abort error, got %v", err)
+		}
+
+		if len(rt.attempts) != 0 {
+			t.Errorf("http request sent after abort")
+		}
+
+		exec.ResetRequestHandlers()
+		if _, err := exec.DoRequest(newRequest()); err != nil {
+			t.Errorf("request hooks not reset: %v", err)
+		}
+	})
+
+	t.Run("do not change shared request", func(t *testing.T) {
+		rt := &blockingRoundTripper{release: make(chan struct{})}
+		close(rt.release)
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.RequestHook(func(next executor.RequestNextHook, ctx context.Context, req *request.Request) error {
+			req.GetParams().Set("sig", "signature")
+			return next(ctx, req)
+		})
+
+		template := newRequest()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := exec.DoRequest(template)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if executor.GetRequest(res.Context()).GetParams().Get("sig") != "signature" {
+					t.Errorf("hook changes not visible in request of response context")
+				}
+			}()
+		}
+		wg.Wait()
+
+		if template.GetParams().Has("sig") {
+			t.Errorf("hook changed shared request: %s", template.GetParams())
+		}
+	})
+
+	t.Run("request done hook receives network errors", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){networkError},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		var doneErr error
+		calls := 0
+		exec.RequestDoneHook(func(ctx context.Context, req *request.Request, res response.Response, err error) {
+			calls++
+			doneErr = err
+			if res != nil || req.GetMethod() != "users.get" || executor.GetAttempt(ctx) != 1 {
+				t.Errorf("unexpected done hook arguments: %v, %s, attempt %d", res, req.GetMethod(), executor.GetAttempt(ctx))
+			}
+		})
+
+		_, err := exec.DoRequest(newRequest())
+		if err == nil || doneErr != err || calls != 1 {
+			t.Errorf("expected one done hook call with %v, got %d calls with %v", err, calls, doneErr)
+		}
+	})
+}
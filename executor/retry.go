@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// Политика повторной отправки запроса.
+// Вызывается executor'ом после каждой попытки отправки HTTP запроса
+type RetryPolicy interface {
+	// Возвращает задержку перед следующей попыткой и признак того, что запрос нужно повторить.
+	// Номер текущей попытки можно получить через executor.GetAttempt(ctx).
+	// Если HTTP запрос завершился ошибкой, res будет равен nil
+	Retry(ctx context.Context, res *http.Response, err error) (time.Duration, bool)
+}
+
+// Политика повторов с экспоненциальной задержкой и случайным разбросом.
+// Повторяет запрос при сетевых ошибках, ответах 5xx и HTML страницах шлюза вместо ответа API
+type BackoffRetryPolicy struct {
+	MaxAttempts int           // Максимальное количество попыток, включая первую
+	BaseDelay   time.Duration // Задержка перед второй попыткой, далее она удваивается с каждой попыткой
+	MaxDelay    time.Duration // Максимальная задержка между попытками, 0 - без ограничения
+	Jitter      float64       // Доля случайного разброса задержки, от 0 до 1
+}
+
+// Создает политику повторов с экспоненциальной задержкой.
+// maxAttempts - максимальное количество попыток, включая первую
+func NewBackoffRetryPolicy(maxAttempts int) *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.5,
+	}
+}
+
+// Реализует интерфейс RetryPolicy
+func (v *BackoffRetryPolicy) Retry(ctx context.Context, res *http.Response, err error) (time.Duration, bool) {
+	attempt := GetAttempt(ctx)
+	if attempt >= v.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	if !IsTemporaryHttpError(res, err) {
+		return 0, false
+	}
+
+	return v.Delay(attempt), true
+}
+
+// Возвращает задержку после попытки с номером attempt
+func (v *BackoffRetryPolicy) Delay(attempt int) time.Duration {
+	delay := v.BaseDelay
+	for i := 1; i < attempt && (v.MaxDelay <= 0 || delay < v.MaxDelay); i++ {
+		// Без ограничения MaxDelay удвоение останавливается до переполнения
+		if delay > math.MaxInt64/2 {
+			delay = math.MaxInt64
+			break
+		}
+		delay *= 2
+	}
+
+	if v.MaxDelay > 0 && delay > v.MaxDelay {
+		delay = v.MaxDelay
+	}
+
+	if v.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * v.Jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// Сообщает, является ли результат HTTP запроса временной ошибкой, после которой запрос стоит повторить:
+// сетевая ошибка, ответ с кодом 5xx или HTML страница вместо ответа API (например, от балансировщика).
+// Ошибки, оборачивающие executor.ErrNotRetryable, временными не считаются
+func IsTemporaryHttpError(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrNotRetryable)
+	}
+
+	if res == nil {
+		return false
+	}
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	return mediaType == "text/html"
+}
+
+// Ждет указанное время или завершения контекста
+func sleepCtx(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
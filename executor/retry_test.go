@@ -0,0 +1,194 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Транспорт, возвращающий заранее заданные ответы по порядку
+type scriptedRoundTripper struct {
+	responses []func(req *http.Request) (*http.Response, error)
+	attempts  []int
+}
+
+func (v *scriptedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v.attempts = append(v.attempts, executor.GetAttempt(req.Context()))
+	i := len(v.attempts) - 1
+	if i >= len(v.responses) {
+		i = len(v.responses) - 1
+	}
+	return v.responses[i](req)
+}
+
+func textResponse(status int, contentType, body string) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {contentType}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+}
+
+func networkError(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("connection reset by peer")
+}
+
+func TestRetryPolicy(t *testing.T) {
+	okResponse := textResponse(http.StatusOK, "application/json", `{"response":1}`)
+
+	newExecutor := func(rt http.RoundTripper, maxAttempts int) *executor.Executor {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		policy := executor.NewBackoffRetryPolicy(maxAttempts)
+		policy.BaseDelay = time.Millisecond
+		exec.RetryPolicy = policy
+		return exec
+	}
+
+	newRequest := func() *request.Request {
+		req := request.New()
+		req.Method("users.get")
+		return req
+	}
+
+	t.Run("retry network errors and gateway pages", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				networkError,
+				textResponse(http.StatusBadGateway, "text/html", "<html>502 Bad Gateway</html>"),
+				textResponse(http.StatusOK, "text/html; charset=utf-8", "<html>maintenance</html>"),
+				okResponse,
+			},
+		}
+
+		res, err := newExecutor(rt, 5).DoRequest(newRequest())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if executor.GetAttempt(res.Context()) != 4 {
+			t.Errorf("expected response from attempt 4, got %d", executor.GetAttempt(res.Context()))
+		}
+
+		expectedAttempts := []int{1, 2, 3, 4}
+		if len(rt.attempts) != len(expectedAttempts) {
+			t.Fatalf("expected attempts: %v, got: %v", expectedAttempts, rt.attempts)
+		}
+		for i := range expectedAttempts {
+			if rt.attempts[i] != expectedAttempts[i] {
+				t.Errorf("expected attempts: %v, got: %v", expectedAttempts, rt.attempts)
+			}
+		}
+	})
+
+	t.Run("stop after max attempts", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){networkError},
+		}
+
+		_, err := newExecutor(rt, 3).DoRequest(newRequest())
+		if err == nil {
+			t.Errorf("expected http error")
+		}
+
+		if len(rt.attempts) != 3 {
+			t.Errorf("expected 3 attempts, got %d", len(rt.attempts))
+		}
+	})
+
+	t.Run("do not retry api responses", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				textResponse(http.StatusOK, "application/json", `{"error":{"error_code":5,"error_msg":"User authorization failed"}}`),
+			},
+		}
+
+		_, err := newExecutor(rt, 3).DoRequest(newRequest())
+		if err == nil {
+			t.Errorf("expected api error")
+		}
+
+		if len(rt.attempts) != 1 {
+			t.Errorf("expected 1 attempt, got %d", len(rt.attempts))
+		}
+	})
+
+	t.Run("do not retry without policy", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){networkError, okResponse},
+		}
+
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+
+		if _, err := exec.DoRequest(newRequest()); err == nil {
+			t.Errorf("expected http error")
+		}
+
+		if len(rt.attempts) != 1 {
+			t.Errorf("expected 1 attempt, got %d", len(rt.attempts))
+		}
+	})
+
+	t.Run("stop waiting on context cancel", func(t *testing.T) {
+		rt := &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){networkError},
+		}
+
+		exec := newExecutor(rt, 10)
+		exec.RetryPolicy.(*executor.BackoffRetryPolicy).BaseDelay = time.Hour
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := exec.DoRequestCtx(ctx, newRequest())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded error, got %v", err)
+		}
+	})
+
+	t.Run("exponential delay limited by max delay", func(t *testing.T) {
+		policy := executor.NewBackoffRetryPolicy(10)
+		policy.Jitter = 0
+		policy.BaseDelay = time.Second
+		policy.MaxDelay = 5 * time.Second
+
+		expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+		for i, delay := range expected {
+			if policy.Delay(i+1) != delay {
+				t.Errorf("attempt %d: expected delay %s, got %s", i+1, delay, policy.Delay(i+1))
+			}
+		}
+	})
+	t.Run("exponential delay without max delay", func(t *testing.T) {
+		policy := &executor.BackoffRetryPolicy{BaseDelay: time.Second}
+
+		expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+		for i, delay := range expected {
+			if policy.Delay(i+1) != delay {
+				t.Errorf("attempt %d: expected delay %s, got %s", i+1, delay, policy.Delay(i+1))
+			}
+		}
+
+		for _, attempt := range []int{37, 64, 1000} {
+			if delay := policy.Delay(attempt); delay < policy.Delay(attempt-1) || delay <= 0 {
+				t.Errorf("attempt %d: delay overflowed: %s", attempt, delay)
+			}
+		}
+
+		policy.Jitter = 0.5
+		if delay := policy.Delay(1000); delay <= 0 {
+			t.Errorf("delay with jitter overflowed: %s", delay)
+		}
+	})
+}
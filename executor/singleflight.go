@@ -0,0 +1,163 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Методы только для чтения, одинаковые запросы к которым объединяются по умолчанию
+var DefaultSingleflightMethods = []string{
+	"users.get",
+	"groups.getById",
+	"utils.resolveScreenName",
+	"wall.getById",
+	"photos.getById",
+	"video.get",
+	"database.getCountriesById",
+	"database.getCitiesById",
+}
+
+// Объединяет одинаковые запросы, выполняющиеся одновременно, в один HTTP запрос.
+// Тело общего ответа читается в память с ограничением размера парсера (jsonresponseparser.JsonResponseParser.MaxBodySize),
+// и каждый запрос получает свою копию HTTP ответа, поэтому хуки и парсер выполняются для каждого запроса отдельно.
+// Если тело больше ограничения, всем ожидающим запросам возвращается ошибка response.ErrBodyTooLarge.
+// Запросы с потоковым разбором ответа (executor.DoRequestTo) не объединяются.
+// Запросы считаются одинаковыми, если совпадают URL пути к API, метод и параметры. Заголовки запросов не учитываются
+//
+//	exec.Singleflight = executor.NewSingleflight("users.get", "groups.getById")
+type Singleflight struct {
+	// Методы, запросы к которым можно объединять. Объединять стоит только методы, не изменяющие данные
+	Methods map[string]bool
+	// Учитывать ли токен в отпечатке запроса. Если false, запросы с разными токенами объединяются,
+	// что допустимо только для методов, ответ которых не зависит от пользователя
+	IncludeToken bool
+
+	mu    sync.Mutex
+	calls map[string]*sharedCall
+}
+
+// Выполняющийся общий HTTP запрос
+type sharedCall struct {
+	done chan struct{}
+	res  *http.Response
+	body []byte
+	err  error
+}
+
+// Создает объединитель запросов для указанных методов.
+// Если методы не указаны, используется DefaultSingleflightMethods
+func NewSingleflight(methods ...string) *Singleflight {
+	if len(methods) == 0 {
+		methods = DefaultSingleflightMethods
+	}
+
+	allowed := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		allowed[method] = true
+	}
+
+	return &Singleflight{
+		Methods:      allowed,
+		IncludeToken: true,
+		calls:        map[string]*sharedCall{},
+	}
+}
+
+// Сообщает, можно ли объединять запросы к методу запроса
+func (v *Singleflight) Allowed(req *request.Request) bool {
+	return v.Methods[req.GetMethod()]
+}
+
+// Возвращает отпечаток запроса с учетом настройки IncludeToken
+func (v *Singleflight) Fingerprint(req *request.Request) string {
+	return req.Fingerprint(v.IncludeToken)
+}
+
+// Выполняет send или ждет результата такого же выполняющегося запроса
+func (v *Singleflight) do(
+	ctx context.Context,
+	req *request.Request,
+	httpReq *http.Request,
+	maxBodySize int64,
+	send func() (*http.Response, error),
+) (*http.Response, error) {
+	// Запросы с разными ограничениями размера тела не объединяются
+	key := v.Fingerprint(req) + "\x00" + strconv.FormatInt(maxBodySize, 10)
+
+	v.mu.Lock()
+	if v.calls == nil {
+		v.calls = map[string]*sharedCall{}
+	}
+
+	if call, ok := v.calls[key]; ok {
+		v.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		// Запрос, отмененный контекстом другого вызова, выполняется заново
+		if isContextError(call.err) && ctx.Err() == nil {
+			return send()
+		}
+
+		return call.response(ctx, httpReq)
+	}
+
+	call := &sharedCall{done: make(chan struct{})}
+	v.calls[key] = call
+	v.mu.Unlock()
+
+	call.res, call.err = send()
+	if call.err == nil {
+		call.body, call.err = io.ReadAll(response.LimitBody(call.res.Body, maxBodySize))
+		call.res.Body.Close()
+
+		if errors.Is(call.err, response.ErrBodyTooLarge) {
+			// Повтор запроса вернет такое же тело, поэтому ошибка не повторяется политикой повторов
+			call.err = fmt.Errorf("%w: %w", response.ErrBodyTooLarge, ErrNotRetryable)
+		}
+	}
+
+	v.mu.Lock()
+	delete(v.calls, key)
+	v.mu.Unlock()
+	close(call.done)
+
+	return call.response(ctx, httpReq)
+}
+
+// Возвращает копию общего ответа для отдельного запроса
+func (c *sharedCall) response(ctx context.Context, httpReq *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &http.Response{
+		Status:        c.res.Status,
+		StatusCode:    c.res.StatusCode,
+		Proto:         c.res.Proto,
+		ProtoMajor:    c.res.ProtoMajor,
+		ProtoMinor:    c.res.ProtoMinor,
+		Header:        c.res.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       httpReq.WithContext(ctx),
+	}, nil
+}
+
+// Сообщает, что ошибка вызвана завершением контекста
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
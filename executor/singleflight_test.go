@@ -0,0 +1,212 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/jsonresponseparser"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Транспорт, задерживающий ответы до закрытия release
+type blockingRoundTripper struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (v *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&v.calls, 1)
+	<-v.release
+	return textResponse(http.StatusOK, "application/json", `{"response":[{"id":1}]}`)(req)
+}
+
+func TestSingleflight(t *testing.T) {
+	newRequest := func(method, token string) *request.Request {
+		req := request.New()
+		req.Method(method)
+		req.GetParams().Set("user_ids", "1")
+		req.GetParams().AccessToken(token)
+		return req
+	}
+
+	doConcurrently := func(exec *executor.Executor, reqs ...*request.Request) []response.Response {
+		responses := make([]response.Response, len(reqs))
+		var wg sync.WaitGroup
+		for i, req := range reqs {
+			wg.Add(1)
+			go func(i int, req *request.Request) {
+				defer wg.Done()
+				res, err := exec.DoRequest(req)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				responses[i] = res
+			}(i, req)
+		}
+		wg.Wait()
+		return responses
+	}
+
+	newExecutor := func(rt http.RoundTripper, sf *executor.Singleflight) *executor.Executor {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rt}
+		exec.Singleflight = sf
+		return exec
+	}
+
+	release := func(rt *blockingRoundTripper) {
+		time.Sleep(50 * time.Millisecond)
+		close(rt.release)
+	}
+
+	t.Run("identical requests share one http call", func(t *testing.T) {
+		rt := &blockingRoundTripper{release: make(chan struct{})}
+		exec := newExecutor(rt, executor.NewSingleflight())
+
+		var hooks int32
+		exec.ApiResponseHook(func(next executor.ApiResponseNextHook, res response.Response) error {
+			atomic.AddInt32(&hooks, 1)
+			return next(res)
+		})
+
+		go release(rt)
+		responses := doConcurrently(exec,
+			newRequest("users.get", "token"),
+			newRequest("users.get", "token"),
+			newRequest("users.get", "token"),
+		)
+
+		if calls := atomic.LoadInt32(&rt.calls); calls != 1 {
+			t.Errorf("expected one http call, got %d", calls)
+		}
+
+		if hooks != 3 {
+			t.Errorf("hooks must run for each caller, got %d", hooks)
+		}
+
+		for _, res := range responses {
+			if res == nil || res.String() != `{"response":[{"id":1}]}` {
+				t.Fatalf("unexpected response: %v", res)
+			}
+		}
+
+		if responses[0] == responses[1] || responses[0].HttpResponse() == responses[1].HttpResponse() {
+			t.Errorf("each caller must get own response")
+		}
+	})
+
+	t.Run("requests with different tokens are not shared", func(t *testing.T) {
+		rt := &blockingRoundTripper{release: make(chan struct{})}
+		exec := newExecutor(rt, executor.NewSingleflight())
+
+		go release(rt)
+		doConcurrently(exec, newRequest("users.get", "token1"), newRequest("users.get", "token2"))
+
+		if calls := atomic.LoadInt32(&rt.calls); calls != 2 {
+			t.Errorf("expected two http calls, got %d", calls)
+		}
+	})
+
+	t.Run("token is ignored if configured", func(t *testing.T) {
+		rt := &blockingRoundTripper{release: make(chan struct{})}
+		sf := executor.NewSingleflight()
+		sf.IncludeToken = false
+		exec := newExecutor(rt, sf)
+
+		go release(rt)
+		doConcurrently(exec, newRequest("users.get", "token1"), newRequest("users.get", "token2"))
+
+		if calls := atomic.LoadInt32(&rt.calls); calls != 1 {
+			t.Errorf("expected one http call, got %d", calls)
+		}
+	})
+
+	t.Run("methods outside allowlist are not shared", func(t *testing.T) {
+		rt := &blockingRoundTripper{release: make(chan struct{})}
+		exec := newExecutor(rt, executor.NewSingleflight())
+
+		go release(rt)
+		doConcurrently(exec, newRequest("messages.send", "token"), newRequest("messages.send", "token"))
+
+		if calls := atomic.LoadInt32(&rt.calls); calls != 2 {
+			t.Errorf("expected two http calls, got %d", calls)
+		}
+	})
+
+	t.Run("body over limit fails every caller", func(t *testing.T) {
+		rt := &blockingRoundTripper{release: make(chan struct{})}
+		exec := newExecutor(rt, executor.NewSingleflight())
+		parser := &jsonresponseparser.JsonResponseParser{MaxBodySize: 8}
+
+		errs := make([]error, 3)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = exec.DoRequestCtxParser(context.Background(), newRequest("users.get", "token"), parser)
+			}(i)
+		}
+		go release(rt)
+		wg.Wait()
+
+		if calls := atomic.LoadInt32(&rt.calls); calls != 1 {
+			t.Errorf("expected one http call, got %d", calls)
+		}
+
+		for _, err := range errs {
+			if !errors.Is(err, response.ErrBodyTooLarge) {
+				t.Errorf("expected body too large error, got: %v", err)
+			}
+		}
+	})
+
+	t.Run("stream responses are not shared", func(t *testing.T) {
+		rt := &blockingRoundTripper{release: make(chan struct{})}
+		exec := newExecutor(rt, executor.NewSingleflight())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := exec.DoRequestTo(context.Background(), newRequest("users.get", "token"), io.Discard); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		go release(rt)
+		wg.Wait()
+
+		if calls := atomic.LoadInt32(&rt.calls); calls != 2 {
+			t.Errorf("expected two http calls, got %d", calls)
+		}
+	})
+
+	t.Run("fingerprint does not depend on params order", func(t *testing.T) {
+		sf := executor.NewSingleflight()
+
+		first := request.New()
+		first.Method("users.get")
+		first.GetParams().Set("user_ids", "1")
+		first.GetParams().Set("fields", "photo_100")
+
+		second := request.New()
+		second.Method("users.get")
+		second.GetParams().Set("fields", "photo_100")
+		second.GetParams().Set("user_ids", "1")
+
+		if sf.Fingerprint(first) != sf.Fingerprint(second) {
+			t.Errorf("fingerprints must be equal")
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/cache"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/jsonresponseparser"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+func TestDoRequestTo(t *testing.T) {
+	newExecutor := func(body string) *executor.Executor {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: &scriptedRoundTripper{
+			responses: []func(req *http.Request) (*http.Response, error){
+				textResponse(http.StatusOK, "application/json", body),
+			},
+		}}
+		return exec
+	}
+
+	req := request.New()
+	req.Method("execute")
+
+	t.Run("pipe response body", func(t *testing.T) {
+		body := `{"response":[{"count":2,"items":[1,2]},{"items":["a\"}","b"]}]}`
+		out := bytes.Buffer{}
+
+		res, err := newExecutor(body).DoRequestTo(context.Background(), req, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out.String() != body {
+			t.Errorf("expected body: %s\ngot: %s", body, out.String())
+		}
+
+		if len(res.Body()) != 0 {
+			t.Errorf("response body buffered: %s", res.Body())
+		}
+	})
+
+	t.Run("api errors", func(t *testing.T) {
+		out := bytes.Buffer{}
+		_, err := newExecutor(`{"error":{"error_code":6,"error_msg":"Too many requests per second"}}`).DoRequestTo(context.Background(), req, &out)
+
+		var apiError *response.Error
+		if !errors.As(err, &apiError) || apiError.IntCode() != 6 {
+			t.Errorf("expected api error, got %v", err)
+		}
+
+		_, err = newExecutor(`{"response":[false],"execute_errors":[{"method":"wall.get","error_code":15,"error_msg":"Access denied"}]}`).
+			DoRequestTo(context.Background(), req, &out)
+
+		var executeErrors *response.ExecuteErrors
+		if !errors.As(err, &executeErrors) || executeErrors.Errors()[0].IntCode() != 15 {
+			t.Errorf("expected execute errors, got %v", err)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		if _, err := newExecutor(`{"response":[1,}`).DoRequestTo(context.Background(), req, &bytes.Buffer{}); err == nil {
+			t.Errorf("expected parse error")
+		}
+	})
+
+	t.Run("max body size", func(t *testing.T) {
+		body := `{"response":"` + strings.Repeat("a", 100) + `"}`
+
+		exec := newExecutor(body)
+		exec.ResponseParser = &jsonresponseparser.JsonResponseParser{MaxBodySize: 50}
+
+		if _, err := exec.DoRequestTo(context.Background(), req, &bytes.Buffer{}); !errors.Is(err, response.ErrBodyTooLarge) {
+			t.Errorf("expected body too large error, got %v", err)
+		}
+
+		if _, err := exec.DoRequest(req); !errors.Is(err, response.ErrBodyTooLarge) {
+			t.Errorf("expected body too large error, got %v", err)
+		}
+
+		exec = newExecutor(body)
+		exec.ResponseParser = &jsonresponseparser.JsonResponseParser{MaxBodySize: int64(len(body))}
+
+		if _, err := exec.DoRequest(req); err != nil {
+			t.Errorf("body with max size not parsed: %v", err)
+		}
+	})
+
+	t.Run("do not record streamed responses in cache", func(t *testing.T) {
+		store := cache.NewMemoryStore(10)
+		responseCache := cache.New(store)
+		responseCache.TTL = map[string]time.Duration{"execute": time.Minute}
+
+		exec := newExecutor(`{"response":1}`)
+		exec.Cache = responseCache
+
+		if _, err := exec.DoRequestTo(context.Background(), req, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if store.Len() != 0 {
+			t.Errorf("streamed response saved in cache")
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Источник токенов доступа для запросов, например tokenpool.Pool
+type TokenProvider interface {
+	// Выбирает токен для очередной попытки выполнения запроса
+	Token(ctx context.Context, req *request.Request) (string, error)
+	// Сообщает результат выполнения запроса с токеном: err - ошибка VK API из ответа или nil.
+	// Возвращает true, если запрос нужно переотправить с другим токеном
+	Release(ctx context.Context, token string, err error) bool
+}
@@ -1,6 +1,6 @@
 module github.com/ciricc/vkapiexecutor
 
-go 1.20
+go 1.21
 
 require (
 	github.com/buger/jsonparser v1.1.1
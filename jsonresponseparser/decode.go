@@ -0,0 +1,70 @@
+package jsonresponseparser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+)
+
+// Максимальная длина фрагмента тела ответа в ошибке декодирования
+var DecodeErrorExcerptLength = 256
+
+// Ошибка декодирования поля response в пользовательский тип
+type DecodeError struct {
+	Err     error  // Исходная ошибка декодирования
+	Excerpt string // Начало тела ответа
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode response error: %s, body: %q", e.Err, e.Excerpt)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Декодирует поле response ответа в target с помощью encoding/json.
+// Для ответов, полученных из ExecuteResponses(), декодируется результат одного вызова.
+// Если поля response нет, а ответ содержит ошибку VK API, возвращается эта ошибка
+func (v *JsonResponse) Decode(target any) error {
+	value, dataType, _, err := jsonparser.Get(v.Body(), "response")
+	if err != nil {
+		if apiError := v.Error(); apiError != nil {
+			return apiError
+		}
+		return v.decodeError(fmt.Errorf("response field not found: %w", err))
+	}
+
+	if err := json.Unmarshal(RawJson(value, dataType), target); err != nil {
+		return v.decodeError(err)
+	}
+
+	return nil
+}
+
+// Создает ошибку декодирования с фрагментом тела ответа
+func (v *JsonResponse) decodeError(err error) *DecodeError {
+	excerpt := v.Body()
+	if len(excerpt) > DecodeErrorExcerptLength {
+		excerpt = excerpt[:DecodeErrorExcerptLength]
+	}
+
+	return &DecodeError{
+		Err:     err,
+		Excerpt: string(excerpt),
+	}
+}
+
+// Возвращает значение, полученное через jsonparser, в виде JSON.
+// jsonparser возвращает строки без кавычек, поэтому они заключаются в кавычки заново
+func RawJson(value []byte, dataType jsonparser.ValueType) []byte {
+	if dataType != jsonparser.String {
+		return value
+	}
+
+	quoted := make([]byte, 0, len(value)+2)
+	quoted = append(quoted, '"')
+	quoted = append(quoted, value...)
+	return append(quoted, '"')
+}
@@ -0,0 +1,72 @@
+package jsonresponseparser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+)
+
+// Разделяет ответ метода execute, вернувшего массив результатов, на отдельные ответы.
+// Каждый элемент массива становится ответом вида {"response": ...}.
+// Элементы со значением false сопоставляются по порядку с ошибками из execute_errors
+// и становятся ответами вида {"error": ...}, поэтому Error() каждого ответа возвращает его собственную ошибку
+func (v *JsonResponse) ExecuteResponses() ([]*JsonResponse, error) {
+	body := v.Body()
+
+	if errorObject, _, _, err := jsonparser.Get(body, "error"); err == nil {
+		if apiError := parseError(errorObject); apiError != nil {
+			return nil, apiError
+		}
+	}
+
+	var executeErrors [][]byte
+	if errorsArray, dataType, _, err := jsonparser.Get(body, "execute_errors"); err == nil && dataType == jsonparser.Array {
+		_, err := jsonparser.ArrayEach(errorsArray, func(errorObject []byte, _ jsonparser.ValueType, _ int, _ error) {
+			executeErrors = append(executeErrors, errorObject)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("parse execute errors: %w", err)
+		}
+	}
+
+	results, dataType, _, err := jsonparser.Get(body, "response")
+	if err != nil {
+		return nil, fmt.Errorf("execute response not found: %w", err)
+	}
+
+	if dataType != jsonparser.Array {
+		return nil, errors.New("execute response is not an array")
+	}
+
+	var responses []*JsonResponse
+	_, err = jsonparser.ArrayEach(results, func(value []byte, dataType jsonparser.ValueType, _ int, _ error) {
+		var slotBody []byte
+
+		switch {
+		case dataType == jsonparser.Boolean && string(value) == "false" && len(executeErrors) > 0:
+			slotBody = wrapJson("error", executeErrors[0], jsonparser.Object)
+			executeErrors = executeErrors[1:]
+		default:
+			slotBody = wrapJson("response", value, dataType)
+		}
+
+		responses = append(responses, NewApiJsonResponseWithBody(v.HttpResponse(), slotBody))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse execute response: %w", err)
+	}
+
+	return responses, nil
+}
+
+// Оборачивает значение в объект с одним ключом
+func wrapJson(key string, value []byte, dataType jsonparser.ValueType) []byte {
+	wrapped := make([]byte, 0, len(value)+len(key)+8)
+	wrapped = append(wrapped, `{"`...)
+	wrapped = append(wrapped, key...)
+	wrapped = append(wrapped, `":`...)
+	wrapped = append(wrapped, RawJson(value, dataType)...)
+
+	return append(wrapped, '}')
+}
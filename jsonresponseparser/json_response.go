@@ -1,8 +1,9 @@
 package jsonresponseparser
 
 import (
-	"encoding/json"
+	"bytes"
 	"net/http"
+	"net/url"
 
 	"github.com/buger/jsonparser"
 	response "github.com/ciricc/vkapiexecutor/response"
@@ -20,10 +21,17 @@ func NewApiJsonResponse(httpResponse *http.Response) *JsonResponse {
 	return res
 }
 
-// Валидирует JSON
+// Создает ответ с уже прочитанным телом
+func NewApiJsonResponseWithBody(httpResponse *http.Response, body []byte) *JsonResponse {
+	return &JsonResponse{
+		*response.NewUnknownWithBody(httpResponse, body),
+	}
+}
+
+// Валидирует JSON.
+// Тело разбирается на токены без построения значений, поэтому проверка не требует дополнительной памяти под весь ответ
 func (v *JsonResponse) ValidateJson() error {
-	t := map[string]interface{}{}
-	return json.Unmarshal(v.Body(), &t)
+	return validateJsonStream(bytes.NewReader(v.Body()))
 }
 
 // Возвращает информацию об ошибке выполнения метода
@@ -59,22 +67,11 @@ func (v *JsonResponse) Error() error {
 	apiErrors := make([]*response.Error, len(errorObjects))
 
 	for i := range errorObjects {
-		errorObject := errorObjects[i]
-
-		errorMessage, _ := jsonparser.GetString(errorObject, "error_msg")
-		errorMessageIntCode, _ := jsonparser.GetInt(errorObject, "error_code")
-
-		if errorMessage == "" && errorMessageIntCode == 0 {
+		apiError := parseError(errorObjects[i])
+		if apiError == nil {
 			return nil
 		}
 
-		apiError := response.NewError(errorMessage, int(errorMessageIntCode))
-
-		apiError.RedirectUri, _ = jsonparser.GetString(errorObject, "redirect_uri")
-		apiError.CaptchaImg, _ = jsonparser.GetString(errorObject, "captcha_img")
-		apiError.CaptchaSid, _ = jsonparser.GetString(errorObject, "captcha_sid")
-		apiError.Method, _ = jsonparser.GetString(errorObject, "method")
-
 		apiErrors[i] = apiError
 	}
 
@@ -88,3 +85,66 @@ func (v *JsonResponse) Error() error {
 
 	return response.NewExecuteErrors(apiErrors)
 }
+
+// Разбирает объект ошибки VK API. Возвращает nil, если объект не содержит ошибки
+func parseError(errorObject []byte) *response.Error {
+	errorMessage, _ := jsonparser.GetString(errorObject, "error_msg")
+	errorMessageIntCode, _ := jsonparser.GetInt(errorObject, "error_code")
+
+	if errorMessage == "" && errorMessageIntCode == 0 {
+		return nil
+	}
+
+	apiError := response.NewError(errorMessage, int(errorMessageIntCode))
+
+	apiError.RedirectUri, _ = jsonparser.GetString(errorObject, "redirect_uri")
+	apiError.CaptchaImg, _ = jsonparser.GetString(errorObject, "captcha_img")
+	apiError.CaptchaSid, _ = jsonparser.GetString(errorObject, "captcha_sid")
+	apiError.Method, _ = jsonparser.GetString(errorObject, "method")
+
+	subcode, _ := jsonparser.GetInt(errorObject, "error_subcode")
+	apiError.Subcode = int(subcode)
+	apiError.Text, _ = jsonparser.GetString(errorObject, "error_text")
+	apiError.CaptchaTs, _ = jsonparser.GetFloat(errorObject, "captcha_ts")
+	captchaAttempt, _ := jsonparser.GetInt(errorObject, "captcha_attempt")
+	apiError.CaptchaAttempt = int(captchaAttempt)
+	apiError.IsRefreshEnabled, _ = jsonparser.GetBoolean(errorObject, "is_refresh_enabled")
+	apiError.ConfirmationText, _ = jsonparser.GetString(errorObject, "confirmation_text")
+	apiError.ViewUrl, _ = jsonparser.GetString(errorObject, "view_url")
+	apiError.RequestParams = parseRequestParams(errorObject)
+	apiError.Raw = append([]byte(nil), errorObject...)
+
+	return apiError
+}
+
+// Разбирает параметры запроса request_params из объекта ошибки.
+// VK API возвращает их массивом объектов {"key": ..., "value": ...}
+func parseRequestParams(errorObject []byte) url.Values {
+	params := url.Values{}
+
+	jsonparser.ArrayEach(errorObject, func(param []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if dataType != jsonparser.Object {
+			return
+		}
+
+		key, err := jsonparser.GetString(param, "key")
+		if err != nil {
+			return
+		}
+
+		value, valueType, _, err := jsonparser.Get(param, "value")
+		if err != nil {
+			return
+		}
+
+		if valueType == jsonparser.String {
+			if unescaped, err := jsonparser.ParseString(value); err == nil {
+				value = []byte(unescaped)
+			}
+		}
+
+		params.Add(key, string(value))
+	}, "request_params")
+
+	return params
+}
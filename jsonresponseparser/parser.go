@@ -7,10 +7,18 @@ import (
 )
 
 // Реализует интерфейс парсера (responseparser.ResponseParser) ответа VK API с поддержкой формата JSON
-type JsonResponseParser struct{}
+type JsonResponseParser struct {
+	// Максимальный размер тела ответа. При превышении возвращается ошибка response.ErrBodyTooLarge.
+	// По умолчанию 0 - без ограничения
+	MaxBodySize int64
+}
 
 // Парсит ответ в формате JSON
-func (*JsonResponseParser) Parse(req *http.Response) (response.Response, error) {
-	jsonResponse := NewApiJsonResponse(req)
+func (v *JsonResponseParser) Parse(req *http.Response) (response.Response, error) {
+	body, err := response.NewUnknownLimit(req, v.MaxBodySize)
+	jsonResponse := &JsonResponse{*body}
+	if err != nil {
+		return jsonResponse, err
+	}
 	return jsonResponse, jsonResponse.ValidateJson()
 }
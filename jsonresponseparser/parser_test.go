@@ -132,6 +132,57 @@ func TestParser(t *testing.T) {
 		require.Equal(t, errorObject.CaptchaSid, "1")
 	})
 
+	t.Run("full error object fields test", func(t *testing.T) {
+		t.Parallel()
+
+		errorJson := `{"error_code":14,"error_msg":"Captcha needed","error_subcode":1200,` +
+			`"error_text":"Please enter the code","captcha_sid":"1","captcha_img":"https://vk.com/captcha.php?sid=1",` +
+			`"captcha_ts":1700000000.123,"captcha_attempt":2,"is_refresh_enabled":true,` +
+			`"confirmation_text":"Confirm action","view_url":"https://vk.com/view","new_field":{"a":1},` +
+			`"request_params":[{"key":"method","value":"wall.post"},{"key":"owner_id","value":"-1"},` +
+			`{"key":"message","value":"line\\nbreak"},{"key":"oauth","value":1}]}`
+
+		res, err := responseParser.Parse(
+			//nolint:exhaustruct
+			&http.Response{
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"response":[false],"execute_errors":[` + errorJson + `]}`,
+				)),
+			})
+		require.NoError(t, err)
+
+		var executeErrors *response.ExecuteErrors
+		require.ErrorAs(t, res.Error(), &executeErrors)
+
+		parsed := []*response.Error{executeErrors.Errors()[0]}
+
+		res, err = responseParser.Parse(
+			//nolint:exhaustruct
+			&http.Response{
+				Body: io.NopCloser(bytes.NewBufferString(`{"error":` + errorJson + `}`)),
+			})
+		require.NoError(t, err)
+
+		var errorObject *response.Error
+		require.ErrorAs(t, res.Error(), &errorObject)
+		parsed = append(parsed, errorObject)
+
+		for _, apiError := range parsed {
+			require.Equal(t, 1200, apiError.Subcode)
+			require.Equal(t, "Please enter the code", apiError.Text)
+			require.Equal(t, 1700000000.123, apiError.CaptchaTs)
+			require.Equal(t, 2, apiError.CaptchaAttempt)
+			require.True(t, apiError.IsRefreshEnabled)
+			require.Equal(t, "Confirm action", apiError.ConfirmationText)
+			require.Equal(t, "https://vk.com/view", apiError.ViewUrl)
+			require.Equal(t, "wall.post", apiError.RequestParams.Get("method"))
+			require.Equal(t, "-1", apiError.RequestParams.Get("owner_id"))
+			require.Equal(t, "line\\nbreak", apiError.RequestParams.Get("message"))
+			require.Equal(t, "1", apiError.RequestParams.Get("oauth"))
+			require.JSONEq(t, errorJson, string(apiError.Raw))
+		}
+	})
+
 	t.Run("error global fields test", func(t *testing.T) {
 		t.Parallel()
 		res, err := responseParser.(*jsonresponseparser.JsonResponseParser).Parse(
@@ -184,4 +235,61 @@ func TestParser(t *testing.T) {
 		require.Equal(t, errorsList[0].Method, "messages.send")
 		require.Equal(t, errorsList[1].IntCode(), 14)
 	})
+
+	t.Run("execute responses split by slots", func(t *testing.T) {
+		t.Parallel()
+		res, err := responseParser.Parse(
+			//nolint:exhaustruct
+			&http.Response{
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"response":[1,false,{"count":0,"items":[]}],"execute_errors":[` +
+						`{"method":"messages.send","error_code":900,"error_msg":"Can't send messages for users from blacklist"}]}`,
+				)),
+			})
+		require.NoError(t, err)
+
+		jsonResponse, ok := res.(*jsonresponseparser.JsonResponse)
+		require.True(t, ok)
+
+		slots, err := jsonResponse.ExecuteResponses()
+		require.NoError(t, err)
+		require.Len(t, slots, 3)
+
+		require.Equal(t, `{"response":1}`, slots[0].String())
+		require.NoError(t, slots[0].Error())
+		require.Equal(t, `{"response":{"count":0,"items":[]}}`, slots[2].String())
+
+		var errorObject *response.Error
+		require.ErrorAs(t, slots[1].Error(), &errorObject)
+		require.Equal(t, 900, errorObject.IntCode())
+		require.Equal(t, "messages.send", errorObject.Method)
+	})
+
+	t.Run("decode response field", func(t *testing.T) {
+		t.Parallel()
+
+		parse := func(body string) *jsonresponseparser.JsonResponse {
+			//nolint:exhaustruct
+			res, err := responseParser.Parse(&http.Response{Body: io.NopCloser(bytes.NewBufferString(body))})
+			require.NoError(t, err)
+			return res.(*jsonresponseparser.JsonResponse)
+		}
+
+		var count struct {
+			Count int `json:"count"`
+		}
+		require.NoError(t, parse(`{"response":{"count":10,"items":[]}}`).Decode(&count))
+		require.Equal(t, 10, count.Count)
+
+		var link string
+		require.NoError(t, parse(`{"response":"https:\/\/vk.cc\/1"}`).Decode(&link))
+		require.Equal(t, "https://vk.cc/1", link)
+
+		var errorObject *response.Error
+		require.ErrorAs(t, parse(`{"error":{"error_code":5,"error_msg":"User authorization failed"}}`).Decode(&link), &errorObject)
+
+		var decodeError *jsonresponseparser.DecodeError
+		require.ErrorAs(t, parse(`{"response":[1]}`).Decode(&link), &decodeError)
+		require.Equal(t, `{"response":[1]}`, decodeError.Excerpt)
+	})
 }
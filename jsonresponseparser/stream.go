@@ -0,0 +1,184 @@
+package jsonresponseparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	response "github.com/ciricc/vkapiexecutor/response"
+)
+
+// Потоковый парсер ответа VK API.
+// Не сохраняет тело ответа в памяти: тело копируется в Writer по мере чтения,
+// а из JSON токенов извлекаются только ошибки error и execute_errors.
+// Body() ответов этого парсера всегда пустой
+type StreamParser struct {
+	Writer      io.Writer // Получатель тела ответа
+	MaxBodySize int64     // Максимальный размер тела ответа, 0 - без ограничения
+}
+
+// Создает потоковый парсер, копирующий тело ответа в w
+func NewStreamParser(w io.Writer) *StreamParser {
+	return &StreamParser{
+		Writer: w,
+	}
+}
+
+// Копирует тело ответа в Writer и возвращает ответ с ошибкой VK API, найденной в теле
+func (v *StreamParser) Parse(res *http.Response) (response.Response, error) {
+	body := io.TeeReader(response.LimitBody(res.Body, v.MaxBodySize), v.Writer)
+
+	apiError, err := StreamError(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Дочитываем тело, чтобы получатель получил его целиком
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, err
+	}
+
+	return &StreamResponse{
+		UnknownResponse: *response.NewUnknownWithBody(res, nil),
+		apiError:        apiError,
+	}, nil
+}
+
+// Ответ потокового парсера
+type StreamResponse struct {
+	response.UnknownResponse
+	apiError error
+}
+
+// Возвращает ошибку VK API, найденную в теле ответа
+func (v *StreamResponse) Error() error {
+	return v.apiError
+}
+
+// Потоково разбирает JSON ответ VK API из r, не загружая его в память целиком.
+// Первым значением возвращает ошибку VK API из полей error или execute_errors (так же, как JsonResponse.Error()),
+// вторым - ошибку чтения или синтаксиса JSON
+func StreamError(r io.Reader) (apiErr error, err error) {
+	dec := json.NewDecoder(r)
+
+	token, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("read json error: %w", err)
+	}
+
+	if token != json.Delim('{') {
+		if err := skipJsonValue(dec, token); err != nil {
+			return nil, err
+		}
+		return nil, expectJsonEnd(dec)
+	}
+
+	var apiError *response.Error
+	var executeErrors []*response.Error
+	isExecuteErrors := false
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("read json error: %w", err)
+		}
+
+		switch key {
+		case "error":
+			var errorObject json.RawMessage
+			if err := dec.Decode(&errorObject); err != nil {
+				return nil, fmt.Errorf("read json error: %w", err)
+			}
+			apiError = parseError(errorObject)
+		case "execute_errors":
+			var errorObjects []json.RawMessage
+			if err := dec.Decode(&errorObjects); err != nil {
+				return nil, fmt.Errorf("read json error: %w", err)
+			}
+			isExecuteErrors = true
+			for _, errorObject := range errorObjects {
+				if executeError := parseError(errorObject); executeError != nil {
+					executeErrors = append(executeErrors, executeError)
+				}
+			}
+		default:
+			token, err := dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("read json error: %w", err)
+			}
+			if err := skipJsonValue(dec, token); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("read json error: %w", err)
+	}
+
+	if err := expectJsonEnd(dec); err != nil {
+		return nil, err
+	}
+
+	if isExecuteErrors && len(executeErrors) > 0 {
+		return response.NewExecuteErrors(executeErrors), nil
+	}
+
+	if apiError != nil {
+		return apiError, nil
+	}
+
+	return nil, nil
+}
+
+// Пропускает значение, начинающееся с токена token, не сохраняя его
+func skipJsonValue(dec *json.Decoder, token json.Token) error {
+	depth := 0
+	for {
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+
+		var err error
+		token, err = dec.Token()
+		if err != nil {
+			return fmt.Errorf("read json error: %w", err)
+		}
+	}
+}
+
+// Проверяет, что после JSON значения нет других данных
+func expectJsonEnd(dec *json.Decoder) error {
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		if err == nil {
+			return errors.New("read json error: unexpected data after top-level value")
+		}
+		return fmt.Errorf("read json error: %w", err)
+	}
+	return nil
+}
+
+// Проверяет синтаксис JSON, разбирая его на токены без построения значений
+func validateJsonStream(r io.Reader) error {
+	dec := json.NewDecoder(r)
+
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("read json error: %w", err)
+	}
+
+	if err := skipJsonValue(dec, token); err != nil {
+		return err
+	}
+
+	return expectJsonEnd(dec)
+}
@@ -0,0 +1,81 @@
+package limiter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/limiter"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Запускайте с флагом -race: тест проверяет, что один шаблон запроса
+// можно выполнять из многих горутин через общий executor и лимитер
+func TestConcurrentRequests(t *testing.T) {
+	var served int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&served, 1)
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("user_ids") != "1" || r.PostForm.Get("access_token") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"response":[{"id":1}]}`))
+	}))
+	defer server.Close()
+
+	exec := executor.New()
+	exec.HttpClient = &http.Client{
+		Transport: limiter.New(1000, time.Minute, time.Minute),
+	}
+	exec.ApiResponseHook(func(next executor.ApiResponseNextHook, res response.Response) error {
+		if executor.GetRequest(res.Context()) == nil {
+			t.Errorf("request not found in response context")
+		}
+		return next(res)
+	})
+
+	templates := make([]*request.Request, 3)
+	for i := range templates {
+		req := request.New()
+		req.Method("users.get")
+		req.BaseUrl(server.URL + "/method/")
+		req.GetParams().AccessToken(string(rune('a' + i)))
+		req.GetParams().Set("user_ids", "1")
+		templates[i] = req
+	}
+
+	const requestsCount = 150
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < requestsCount; i++ {
+		wg.Add(1)
+		go func(req *request.Request) {
+			defer wg.Done()
+
+			res, err := exec.DoRequest(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if res.String() != `{"response":[{"id":1}]}` {
+				t.Errorf("unexpected response: %s", res)
+			}
+
+			if resReq := executor.GetRequest(res.Context()); resReq.GetToken() != req.GetToken() || resReq.GetMethod() != req.GetMethod() {
+				t.Errorf("response context has another request")
+			}
+		}(templates[i%len(templates)])
+	}
+	wg.Wait()
+
+	if atomic.LoadInt32(&served) != requestsCount {
+		t.Errorf("expected %d requests served, got %d", requestsCount, served)
+	}
+}
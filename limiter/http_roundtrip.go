@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/tracing"
 )
 
 // LimiterTripper type
@@ -13,9 +15,16 @@ type LimiterTripper http.RoundTripper
 // Объект, хранящий в себе все лимитеры запросов на конкретный токен.
 // Чтобы не засорять память, в случае, если у вас много (сотни или тысячи) различных токенов,
 // в этом пакете используется кеширование лимитеров. Лимитеры токенов регулярно удаляются из памяти.
+//
+// Чтобы получать метрики ожидания, задайте поле Metrics:
+//
+//	tripper := limiter.New(3, time.Minute, time.Minute).(*limiter.Tripper)
+//	tripper.Metrics = metrics.NewPrometheus()
 type Tripper struct {
 	store   LimiterStore
 	Tripper http.RoundTripper
+	// Получатель метрик времени ожидания и очереди запросов
+	Metrics Metrics
 }
 
 // Возвращает новый лимитер.
@@ -50,10 +59,33 @@ func (v *Tripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 
-	err = limiter.Wait(req.Context())
+	err = v.wait(limiter, req, apiReq)
 	if err != nil {
 		return nil, err
 	}
 
 	return v.Tripper.RoundTrip(req)
 }
+
+// Ждет лимитер внутри span'а vkapi.limiter.wait, передавая время ожидания и размер очереди в метрики
+func (v *Tripper) wait(limiter WaitLimiter, req *http.Request, apiReq *request.Request) error {
+	ctx, span := tracing.Start(req.Context(), "vkapi.limiter.wait")
+	defer span.End()
+
+	if v.Metrics == nil {
+		err := limiter.Wait(ctx)
+		span.RecordError(err)
+		return err
+	}
+
+	method, tokenId := apiReq.GetMethod(), executor.TokenId(apiReq)
+
+	v.Metrics.AddQueueDepth(method, tokenId, 1)
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	span.RecordError(err)
+	v.Metrics.AddQueueDepth(method, tokenId, -1)
+	v.Metrics.ObserveWait(method, tokenId, time.Since(start), err)
+
+	return err
+}
@@ -42,14 +42,19 @@ func (c *limiterStoreTtlCache) GetLimiter(
 		return DefaultLimiter, nil
 	}
 
-	var limiter *rate.Limiter
 	if savedLimiter, ok := c.limitersCache.Get(token); ok {
 		return savedLimiter.(*rate.Limiter), nil
-	} else {
-		limiter = rate.NewLimiter(c.rateLimit, 1)
-		c.limitersCache.Set(token, limiter, cache.DefaultExpiration)
-		return limiter, nil
 	}
+
+	// Add не перезаписывает лимитер, созданный параллельным запросом с тем же токеном
+	limiter := rate.NewLimiter(c.rateLimit, 1)
+	if err := c.limitersCache.Add(token, limiter, cache.DefaultExpiration); err != nil {
+		if savedLimiter, ok := c.limitersCache.Get(token); ok {
+			return savedLimiter.(*rate.Limiter), nil
+		}
+	}
+
+	return limiter, nil
 }
 
 // NewLimiterStoreTtlCache returns limiter's store with ttl cache storage
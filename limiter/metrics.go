@@ -0,0 +1,12 @@
+package limiter
+
+import "time"
+
+// Получатель метрик лимитера. Адаптер для Prometheus находится в пакете metrics.
+// Метки method и tokenId - имя метода и хеш токена запроса (см. executor.TokenId)
+type Metrics interface {
+	// Вызывается после ожидания лимитера. err - ошибка ожидания, например, отмена контекста
+	ObserveWait(method, tokenId string, wait time.Duration, err error)
+	// Изменяет количество запросов, ожидающих лимитер: +1 перед ожиданием и -1 после него
+	AddQueueDepth(method, tokenId string, delta int)
+}
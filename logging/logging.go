@@ -0,0 +1,124 @@
+// Пакет logging реализует хук executor'а для структурированного логирования запросов через log/slog.
+// Параметры запросов логируются без секретов: значения токенов, client_secret, captcha_key и sig скрываются
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Хук логирования ответов VK API.
+// Для каждой попытки запроса записывает метод, параметры без секретов, номер попытки,
+// время выполнения, HTTP статус и код ошибки VK API.
+// Запросы, завершившиеся без ответа VK API (сетевой ошибкой, ошибкой разбора ответа или ошибкой хука),
+// не доходят до хуков API ответа и логируются через RequestDoneHook
+//
+//	hook := logging.New(slog.Default())
+//	exec.ApiResponseHook(hook.ApiResponseHook)
+//	exec.RequestDoneHook(hook.RequestDoneHook)
+type Hook struct {
+	// Логгер
+	Logger *slog.Logger
+	// Сообщение записи
+	Message string
+	// Уровень записей об успешных ответах
+	Level slog.Level
+	// Уровень записей об ответах с ошибкой VK API
+	ErrorLevel slog.Level
+	// Доля успешных ответов, попадающих в лог, от 0 до 1. Значение 0 и меньше - логировать все ответы.
+	// Ответы с ошибкой логируются всегда
+	SampleRate float64
+}
+
+// Создает хук, логирующий все ответы: успешные с уровнем Debug, ошибки с уровнем Warn
+func New(logger *slog.Logger) *Hook {
+	return &Hook{
+		Logger:     logger,
+		Message:    "vk api request",
+		Level:      slog.LevelDebug,
+		ErrorLevel: slog.LevelWarn,
+		SampleRate: 1,
+	}
+}
+
+// Хук для executor.ApiResponseHook()
+func (v *Hook) ApiResponseHook(next executor.ApiResponseNextHook, res response.Response) error {
+	v.log(res)
+	return next(res)
+}
+
+// Хук для executor.RequestDoneHook(). Логирует запросы, завершившиеся без ответа VK API,
+// с уровнем ErrorLevel. Запросы с ответом уже залогированы через ApiResponseHook
+func (v *Hook) RequestDoneHook(ctx context.Context, req *request.Request, res response.Response, err error) {
+	if res != nil || err == nil || !v.Logger.Enabled(ctx, v.ErrorLevel) {
+		return
+	}
+
+	attrs := v.requestAttrs(ctx, req)
+	attrs = append(attrs, slog.String("error", err.Error()))
+
+	v.Logger.LogAttrs(ctx, v.ErrorLevel, v.Message, attrs...)
+}
+
+func (v *Hook) log(res response.Response) {
+	ctx := res.Context()
+
+	var apiErr *response.Error
+	failed := errors.As(res.Error(), &apiErr)
+
+	level := v.Level
+	if failed {
+		level = v.ErrorLevel
+	} else if v.SampleRate > 0 && v.SampleRate < 1 && rand.Float64() >= v.SampleRate {
+		return
+	}
+
+	if !v.Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := v.requestAttrs(ctx, executor.GetRequest(ctx))
+
+	if httpResponse := res.HttpResponse(); httpResponse != nil {
+		attrs = append(attrs, slog.Int("status", httpResponse.StatusCode))
+	}
+
+	if failed {
+		attrs = append(attrs,
+			slog.Int("error_code", apiErr.IntCode()),
+			slog.String("error", apiErr.Error()),
+		)
+	}
+
+	v.Logger.LogAttrs(ctx, level, v.Message, attrs...)
+}
+
+// Возвращает атрибуты запроса и попытки
+func (v *Hook) requestAttrs(ctx context.Context, req *request.Request) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 8)
+
+	if req != nil {
+		attrs = append(attrs,
+			slog.String("method", req.GetMethod()),
+			slog.Any("params", req.GetParams()),
+		)
+		if tokenId := executor.TokenId(req); tokenId != "" {
+			attrs = append(attrs, slog.String("token_id", tokenId))
+		}
+	}
+
+	attrs = append(attrs, slog.Int("attempt", executor.GetAttempt(ctx)))
+
+	if start := executor.GetAttemptStart(ctx); !start.IsZero() {
+		attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+	}
+
+	return attrs
+}
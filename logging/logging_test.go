@@ -0,0 +1,145 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/logging"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+type bodyRoundTripper string
+
+func (v bodyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(v))),
+		Request:    req,
+	}, nil
+}
+
+func TestHook(t *testing.T) {
+	newExecutor := func(body string, hook *logging.Hook) *executor.Executor {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: bodyRoundTripper(body)}
+		exec.ApiResponseHook(hook.ApiResponseHook)
+		exec.RequestDoneHook(hook.RequestDoneHook)
+		return exec
+	}
+
+	newRequest := func() *request.Request {
+		req := request.New()
+		req.Method("users.get")
+		req.GetParams().AccessToken("secret_token")
+		req.GetParams().Set("user_ids", "1")
+		return req
+	}
+
+	t.Run("logs error response without secrets", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := logging.New(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+		exec := newExecutor(`{"error":{"error_code":6,"error_msg":"Too many requests per second"}}`, hook)
+		exec.DoRequest(newRequest())
+
+		if strings.Contains(buf.String(), "secret_token") {
+			t.Fatalf("token leaked: %s", buf.String())
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+
+		params, _ := record["params"].(map[string]any)
+		expected := map[string]any{
+			"level":      "WARN",
+			"method":     "users.get",
+			"attempt":    float64(1),
+			"status":     float64(200),
+			"error_code": float64(6),
+		}
+
+		for key, value := range expected {
+			if record[key] != value {
+				t.Errorf("unexpected %s: %v", key, record[key])
+			}
+		}
+
+		if params["user_ids"] != "1" || params["access_token"] != request.RedactedValue {
+			t.Errorf("unexpected params: %v", params)
+		}
+
+		if _, ok := record["latency"]; !ok {
+			t.Errorf("latency not logged")
+		}
+	})
+
+	t.Run("successful responses are sampled", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := logging.New(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		hook.SampleRate = math.SmallestNonzeroFloat64
+
+		exec := newExecutor(`{"response":1}`, hook)
+		exec.DoRequest(newRequest())
+
+		if buf.Len() != 0 {
+			t.Errorf("sampled out response logged: %s", buf.String())
+		}
+	})
+
+	t.Run("zero sample rate logs all responses", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := &logging.Hook{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+		exec := newExecutor(`{"response":1}`, hook)
+		exec.DoRequest(newRequest())
+
+		if !strings.Contains(buf.String(), `"method":"users.get"`) {
+			t.Errorf("response not logged: %s", buf.String())
+		}
+	})
+
+	t.Run("logs requests without api response", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := logging.New(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+		exec := newExecutor(`{"response":[1,}`, hook)
+		if _, err := exec.DoRequest(newRequest()); err == nil {
+			t.Fatal("expected parse error")
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("parse error not logged: %v", err)
+		}
+
+		if record["level"] != "WARN" || record["method"] != "users.get" || record["error"] == nil {
+			t.Errorf("unexpected record: %v", record)
+		}
+
+		if strings.Contains(buf.String(), "secret_token") {
+			t.Errorf("token leaked: %s", buf.String())
+		}
+	})
+
+	t.Run("level below handler level is skipped", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := logging.New(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+		exec := newExecutor(`{"response":1}`, hook)
+		exec.DoRequest(newRequest())
+
+		if buf.Len() != 0 {
+			t.Errorf("debug record logged: %s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Тип метрики в текстовом формате Prometheus
+type familyType string
+
+const (
+	typeCounter   familyType = "counter"
+	typeGauge     familyType = "gauge"
+	typeHistogram familyType = "histogram"
+)
+
+// Семейство метрик с одинаковым именем и набором меток
+type family struct {
+	name    string
+	help    string
+	typ     familyType
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// Значения одной метрики с конкретными значениями меток
+type series struct {
+	labels  []string
+	value   float64  // Значение счетчика или gauge, сумма наблюдений гистограммы
+	count   uint64   // Количество наблюдений гистограммы
+	buckets []uint64 // Количество наблюдений, не превышающих границу корзины
+}
+
+func newFamily(name, help string, typ familyType, labels ...string) *family {
+	return &family{
+		name:   name,
+		help:   help,
+		typ:    typ,
+		labels: labels,
+		series: map[string]*series{},
+	}
+}
+
+func newHistogram(name, help string, buckets []float64, labels ...string) *family {
+	f := newFamily(name, help, typeHistogram, labels...)
+	f.buckets = buckets
+	return f
+}
+
+// Возвращает метрику по значениям меток. Вызывается под блокировкой
+func (f *family) get(values []string) *series {
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: values}
+		if f.typ == typeHistogram {
+			s.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Прибавляет delta к счетчику или gauge
+func (f *family) add(delta float64, values ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.get(values).value += delta
+}
+
+// Добавляет наблюдение в гистограмму
+func (f *family) observe(value float64, values ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.get(values)
+	s.value += value
+	s.count++
+	for i, bound := range f.buckets {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// Записывает семейство в текстовом формате Prometheus
+func (f *family) write(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.typ != typeHistogram {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, f.formatLabels(s.labels, ""), formatFloat(s.value)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for i, bound := range f.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.formatLabels(s.labels, formatFloat(bound)), s.buckets[i]); err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			f.name, f.formatLabels(s.labels, "+Inf"), s.count,
+			f.name, f.formatLabels(s.labels, ""), formatFloat(s.value),
+			f.name, f.formatLabels(s.labels, ""), s.count,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Форматирует метки метрики. le - граница корзины гистограммы, пустая строка для остальных метрик
+func (f *family) formatLabels(values []string, le string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, name := range f.labels {
+		pairs = append(pairs, name+`="`+labelEscaper.Replace(values[i])+`"`)
+	}
+
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Экранирует значения меток по правилам текстового формата Prometheus
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,114 @@
+// Пакет metrics собирает метрики executor'а и лимитера и отдает их в текстовом формате Prometheus
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Границы корзин гистограмм времени в секундах по умолчанию
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Сборщик метрик в формате Prometheus.
+// Реализует executor.Metrics, limiter.Metrics и http.Handler, отдающий метрики
+//
+//	m := metrics.NewPrometheus()
+//	exec.Metrics = m
+//	http.Handle("/metrics", m)
+type Prometheus struct {
+	requests      *family
+	duration      *family
+	apiErrors     *family
+	retries       *family
+	limiterWait   *family
+	limiterQueue  *family
+	limiterErrors *family
+}
+
+// Создает сборщик метрик с границами корзин гистограмм по умолчанию
+func NewPrometheus() *Prometheus {
+	return NewPrometheusBuckets(DefaultBuckets)
+}
+
+// Создает сборщик метрик с заданными границами корзин гистограмм времени в секундах
+func NewPrometheusBuckets(buckets []float64) *Prometheus {
+	return &Prometheus{
+		requests: newFamily("vkapi_requests_total",
+			"Total number of VK API requests.", typeCounter, "method", "token", "status"),
+		duration: newHistogram("vkapi_request_duration_seconds",
+			"VK API request duration including retries.", buckets, "method", "token"),
+		apiErrors: newFamily("vkapi_api_errors_total",
+			"Total number of VK API error responses by error code.", typeCounter, "method", "token", "code"),
+		retries: newFamily("vkapi_retries_total",
+			"Total number of VK API request retries.", typeCounter, "method", "token", "reason"),
+		limiterWait: newHistogram("vkapi_limiter_wait_seconds",
+			"Time requests spend waiting for the rate limiter.", buckets, "method", "token"),
+		limiterQueue: newFamily("vkapi_limiter_queue_depth",
+			"Number of requests waiting for the rate limiter.", typeGauge, "method", "token"),
+		limiterErrors: newFamily("vkapi_limiter_wait_errors_total",
+			"Total number of rate limiter waits finished with an error.", typeCounter, "method", "token"),
+	}
+}
+
+// Реализует executor.Metrics
+func (v *Prometheus) ObserveRequest(method, tokenId string, duration time.Duration, err error) {
+	v.requests.add(1, method, tokenId, requestStatus(err))
+	v.duration.observe(duration.Seconds(), method, tokenId)
+}
+
+// Реализует executor.Metrics
+func (v *Prometheus) ObserveApiError(method, tokenId string, code int) {
+	v.apiErrors.add(1, method, tokenId, strconv.Itoa(code))
+}
+
+// Реализует executor.Metrics
+func (v *Prometheus) ObserveRetry(method, tokenId, reason string) {
+	v.retries.add(1, method, tokenId, reason)
+}
+
+// Реализует limiter.Metrics
+func (v *Prometheus) ObserveWait(method, tokenId string, wait time.Duration, err error) {
+	v.limiterWait.observe(wait.Seconds(), method, tokenId)
+	if err != nil {
+		v.limiterErrors.add(1, method, tokenId)
+	}
+}
+
+// Реализует limiter.Metrics
+func (v *Prometheus) AddQueueDepth(method, tokenId string, delta int) {
+	v.limiterQueue.add(float64(delta), method, tokenId)
+}
+
+// Отдает метрики в текстовом формате Prometheus
+func (v *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	families := []*family{
+		v.requests, v.duration, v.apiErrors, v.retries,
+		v.limiterWait, v.limiterQueue, v.limiterErrors,
+	}
+
+	for _, f := range families {
+		if err := f.write(w); err != nil {
+			return
+		}
+	}
+}
+
+// Возвращает статус запроса для метки status: ok, api_error или error
+func requestStatus(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	var apiErr *response.Error
+	if errors.As(err, &apiErr) {
+		return "api_error"
+	}
+
+	return "error"
+}
@@ -0,0 +1,88 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/limiter"
+	"github.com/ciricc/vkapiexecutor/metrics"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Транспорт, отвечающий ошибкой 6 на первый запрос и успехом на остальные
+type floodRoundTripper struct {
+	calls int
+}
+
+func (v *floodRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v.calls++
+	body := `{"response":1}`
+	if v.calls == 1 {
+		body = `{"error":{"error_code":6,"error_msg":"Too many requests per second"}}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestPrometheus(t *testing.T) {
+	m := metrics.NewPrometheus()
+
+	tripper := limiter.New(100, time.Minute, time.Minute).(*limiter.Tripper)
+	tripper.Tripper = &floodRoundTripper{}
+	tripper.Metrics = m
+
+	exec := executor.New()
+	exec.HttpClient = &http.Client{Transport: tripper}
+	exec.Metrics = m
+	exec.ApiResponseHook(func(next executor.ApiResponseNextHook, res response.Response) error {
+		if res.Error() != nil {
+			res.Renew(true)
+		}
+		return next(res)
+	})
+
+	req := request.New()
+	req.Method("users.get")
+	req.GetParams().AccessToken("secret_token")
+
+	if _, err := exec.DoRequest(req); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+
+	tokenId := executor.TokenId(req)
+	if tokenId == "" || strings.Contains(body, "secret_token") {
+		t.Fatalf("token must be hashed: %q", tokenId)
+	}
+
+	labels := `method="users.get",token="` + tokenId + `"`
+	expected := []string{
+		"# TYPE vkapi_requests_total counter",
+		`vkapi_requests_total{` + labels + `,status="ok"} 1`,
+		`vkapi_request_duration_seconds_count{` + labels + `} 1`,
+		`vkapi_request_duration_seconds_bucket{` + labels + `,le="+Inf"} 1`,
+		`vkapi_api_errors_total{` + labels + `,code="6"} 1`,
+		`vkapi_retries_total{` + labels + `,reason="renew"} 1`,
+		`vkapi_limiter_wait_seconds_count{` + labels + `} 2`,
+		`vkapi_limiter_queue_depth{` + labels + `} 0`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics do not contain %q:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package paginator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/buger/jsonparser"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Прогресс загрузки списка
+type Progress struct {
+	Total      int // Общее количество элементов списка
+	Collected  int // Количество загруженных элементов, включая повторяющиеся
+	Chunks     int // Количество запросов execute
+	ChunksDone int // Количество выполненных запросов execute
+}
+
+// Параллельно загружает большие списки методов с параметрами offset и count.
+// Страницы запрашиваются пачками до 25 вызовов в одном запросе execute,
+// пачки распределяются между токенами, каждый токен обрабатывает свои пачки последовательно.
+// Для соблюдения лимитов по токену используйте limiter.Tripper в HTTP клиенте executor'а:
+// каждый execute расходует один запрос из лимита токена.
+// Результат собирается в порядке смещений, повторяющиеся элементы удаляются
+type Collector struct {
+	// Токены, между которыми распределяются запросы. Если не заданы, используется токен шаблона запроса
+	Tokens []string
+	// Количество элементов на странице. По умолчанию берется из DefaultPageSizes по методу
+	PageSize int
+	// Количество страниц в одном запросе execute, не больше executor.MaxBatchSize
+	PagesPerExecute int
+	// Вызывается после загрузки каждой пачки страниц
+	Progress func(Progress)
+	// Возвращает ключ элемента для удаления повторов. По умолчанию ItemKey
+	Key func(item json.RawMessage) string
+
+	executor *executor.Executor
+}
+
+// Создает загрузчик списков
+func NewCollector(exec *executor.Executor, tokens ...string) *Collector {
+	return &Collector{
+		Tokens:          tokens,
+		PagesPerExecute: executor.MaxBatchSize,
+		Key:             ItemKey,
+		executor:        exec,
+	}
+}
+
+// Возвращает ключ элемента списка: поле id для объектов, иначе сам элемент
+func ItemKey(item json.RawMessage) string {
+	if id, dataType, _, err := jsonparser.Get(item, "id"); err == nil && dataType != jsonparser.Null {
+		return string(id)
+	}
+	return string(item)
+}
+
+// Загружает весь список по шаблону запроса
+func (v *Collector) Collect(ctx context.Context, req *request.Request) ([]json.RawMessage, error) {
+	pageSize := v.PageSize
+	if pageSize <= 0 {
+		pageSize = NewOffset(v.executor, req).PageSize
+	}
+
+	pagesPerExecute := v.PagesPerExecute
+	if pagesPerExecute <= 0 || pagesPerExecute > executor.MaxBatchSize {
+		pagesPerExecute = executor.MaxBatchSize
+	}
+
+	tokens := v.Tokens
+	if len(tokens) == 0 {
+		tokens = []string{""}
+	}
+
+	firstPage, err := fetchPage(ctx, v.executor, v.pageRequest(req, tokens[0], 0, pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var offsets []int
+	for offset := pageSize; offset < firstPage.Count; offset += pageSize {
+		offsets = append(offsets, offset)
+	}
+
+	var chunks [][]int
+	for len(offsets) > 0 {
+		size := pagesPerExecute
+		if size > len(offsets) {
+			size = len(offsets)
+		}
+		chunks = append(chunks, offsets[:size])
+		offsets = offsets[size:]
+	}
+
+	progress := Progress{
+		Total:     firstPage.Count,
+		Collected: len(firstPage.Items),
+		Chunks:    len(chunks),
+	}
+	v.reportProgress(progress)
+
+	results, err := v.collectChunks(ctx, req, tokens, pageSize, chunks, &progress)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.merge(firstPage.Items, results), nil
+}
+
+// Загружает пачки страниц параллельно по токенам
+func (v *Collector) collectChunks(
+	ctx context.Context,
+	req *request.Request,
+	tokens []string,
+	pageSize int,
+	chunks [][]int,
+	progress *Progress,
+) ([][]json.RawMessage, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]json.RawMessage, len(chunks))
+	indexes := make(chan int)
+
+	mu := sync.Mutex{}
+	var firstErr error
+
+	wg := sync.WaitGroup{}
+	for _, token := range tokens {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+
+			for i := range indexes {
+				items, err := v.collectChunk(ctx, req, token, pageSize, chunks[i])
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					cancel()
+				} else {
+					results[i] = items
+					progress.Collected += len(items)
+					progress.ChunksDone++
+					v.reportProgress(*progress)
+				}
+				mu.Unlock()
+			}
+		}(token)
+	}
+
+	for i := range chunks {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Загружает пачку страниц одним запросом execute
+func (v *Collector) collectChunk(ctx context.Context, req *request.Request, token string, pageSize int, offsets []int) ([]json.RawMessage, error) {
+	reqs := make([]*request.Request, len(offsets))
+	for i, offset := range offsets {
+		reqs[i] = v.pageRequest(req, token, offset, pageSize)
+	}
+
+	responses, err := v.executor.DoBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	for i, res := range responses {
+		if err := res.Error(); err != nil {
+			return nil, fmt.Errorf("page with offset %d: %w", offsets[i], err)
+		}
+
+		page, err := parsePage(res.Body())
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+	}
+
+	return items, nil
+}
+
+// Создает копию шаблона запроса для страницы
+func (v *Collector) pageRequest(req *request.Request, token string, offset, pageSize int) *request.Request {
+	pageReq := req.Clone()
+	if pageReq.GetParams() == nil {
+		pageReq.Params(request.NewParams())
+	}
+
+	params := pageReq.GetParams()
+	if token != "" {
+		params.AccessToken(token)
+	}
+	params.Set(OffsetParamKey, strconv.Itoa(offset))
+	params.Set(CountParamKey, strconv.Itoa(pageSize))
+
+	return pageReq
+}
+
+// Объединяет страницы в порядке смещений, удаляя повторы
+func (v *Collector) merge(first []json.RawMessage, chunks [][]json.RawMessage) []json.RawMessage {
+	key := v.Key
+	if key == nil {
+		key = ItemKey
+	}
+
+	seen := map[string]struct{}{}
+	var items []json.RawMessage
+
+	add := func(chunk []json.RawMessage) {
+		for _, item := range chunk {
+			itemKey := key(item)
+			if _, ok := seen[itemKey]; ok {
+				continue
+			}
+			seen[itemKey] = struct{}{}
+			items = append(items, item)
+		}
+	}
+
+	add(first)
+	for _, chunk := range chunks {
+		add(chunk)
+	}
+
+	return items
+}
+
+// Сообщает о прогрессе загрузки
+func (v *Collector) reportProgress(progress Progress) {
+	if v.Progress != nil {
+		v.Progress(progress)
+	}
+}
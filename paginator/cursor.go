@@ -0,0 +1,175 @@
+package paginator
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/buger/jsonparser"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Способ постраничной загрузки метода через курсор
+type CursorMethod struct {
+	Param     string                  // Параметр запроса, в котором передается курсор
+	PageSize  int                     // Количество элементов на странице, 0 - не передавать параметр count
+	Next      func(page *Page) string // Возвращает курсор следующей страницы, пустая строка - страниц больше нет
+	SkipFirst bool                    // Курсор указывает на последний загруженный элемент, который нужно пропустить на следующей странице
+}
+
+// Способы постраничной загрузки методов с курсорами
+var DefaultCursorMethods = map[string]CursorMethod{
+	"newsfeed.get":              {Param: "start_from", PageSize: 100, Next: NextFromCursor("next_from")},
+	"newsfeed.search":           {Param: "start_from", PageSize: 200, Next: NextFromCursor("next_from")},
+	"newsfeed.getComments":      {Param: "start_from", PageSize: 100, Next: NextFromCursor("next_from")},
+	"wall.getComments":          {Param: "start_comment_id", PageSize: 100, Next: LastItemCursor("id"), SkipFirst: true},
+	"messages.getConversations": {Param: "start_message_id", PageSize: 200, Next: LastItemCursor("conversation", "last_message_id"), SkipFirst: true},
+}
+
+// Возвращает функцию, которая берет курсор следующей страницы из поля ответа, например next_from
+func NextFromCursor(key string) func(page *Page) string {
+	return func(page *Page) string {
+		value, dataType, _, err := jsonparser.Get(page.Body, "response", key)
+		if err != nil || dataType == jsonparser.Null {
+			return ""
+		}
+		return cursorString(value, dataType)
+	}
+}
+
+// Возвращает функцию, которая берет курсор следующей страницы из поля последнего элемента страницы,
+// например идентификатор последнего комментария
+func LastItemCursor(keys ...string) func(page *Page) string {
+	return func(page *Page) string {
+		if len(page.Items) == 0 {
+			return ""
+		}
+
+		value, dataType, _, err := jsonparser.Get(page.Items[len(page.Items)-1], keys...)
+		if err != nil || dataType == jsonparser.Null {
+			return ""
+		}
+		return cursorString(value, dataType)
+	}
+}
+
+// Возвращает значение курсора строкой. Экранированные символы строк JSON раскодируются
+func cursorString(value []byte, dataType jsonparser.ValueType) string {
+	if dataType != jsonparser.String {
+		return string(value)
+	}
+
+	cursor, err := jsonparser.ParseString(value)
+	if err != nil {
+		return ""
+	}
+	return cursor
+}
+
+// Постраничная загрузка списков методов с курсорами (start_from/next_from, start_comment_id и т.д).
+// Шаблон запроса не изменяется: каждая страница запрашивается его копией.
+// Текущий курсор можно сохранить через Cursor() и продолжить загрузку с него после перезапуска через SetCursor()
+type Cursor struct {
+	// Способ загрузки метода
+	Method CursorMethod
+
+	executor *executor.Executor
+	request  *request.Request
+
+	mu     sync.Mutex
+	cursor string
+	done   bool
+}
+
+// Создает постраничную загрузку через курсор по шаблону запроса.
+// Способ загрузки берется из DefaultCursorMethods по методу запроса,
+// для остальных методов используются параметры start_from и next_from
+func NewCursor(exec *executor.Executor, req *request.Request) *Cursor {
+	method, ok := DefaultCursorMethods[req.GetMethod()]
+	if !ok {
+		method = CursorMethod{Param: "start_from", Next: NextFromCursor("next_from")}
+	}
+
+	return &Cursor{
+		Method:   method,
+		executor: exec,
+		request:  req,
+	}
+}
+
+// Загружает следующую страницу и сдвигает курсор.
+// Возвращает пустую страницу, если список закончился
+func (v *Cursor) Next(ctx context.Context) (*Page, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.done {
+		return &Page{Count: -1}, nil
+	}
+
+	req := v.request.Clone()
+	if req.GetParams() == nil {
+		req.Params(request.NewParams())
+	}
+
+	params := req.GetParams()
+	if v.Method.PageSize > 0 {
+		params.Set(CountParamKey, strconv.Itoa(v.Method.PageSize))
+	}
+
+	skipFirst := false
+	if v.cursor != "" {
+		params.Set(v.Method.Param, v.cursor)
+		skipFirst = v.Method.SkipFirst
+	}
+
+	page, err := fetchPage(ctx, v.executor, req)
+	if err != nil {
+		return nil, err
+	}
+
+	next := v.Method.Next(page)
+
+	if skipFirst && len(page.Items) > 0 {
+		page.Items = page.Items[1:]
+	}
+
+	v.done = next == "" || next == v.cursor
+	v.cursor = next
+
+	return page, nil
+}
+
+// Сообщает, загружены ли все страницы
+func (v *Cursor) Done() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.done
+}
+
+// Возвращает курсор следующей страницы
+func (v *Cursor) Cursor() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.cursor
+}
+
+// Устанавливает курсор следующей страницы, например, для продолжения загрузки после перезапуска
+func (v *Cursor) SetCursor(cursor string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.cursor = cursor
+	v.done = false
+}
+
+// Возвращает ленивую последовательность элементов всех оставшихся страниц
+func (v *Cursor) All(ctx context.Context) Seq2[json.RawMessage, error] {
+	return all(ctx, v)
+}
+
+// Возвращает канал элементов всех оставшихся страниц
+func (v *Cursor) Chan(ctx context.Context) <-chan Item {
+	return channel(ctx, v)
+}
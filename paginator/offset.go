@@ -0,0 +1,159 @@
+package paginator
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Размер страницы для методов, которых нет в DefaultPageSizes
+var DefaultPageSize = 100
+
+// Максимальные размеры страниц методов, которые постранично загружаются через offset и count
+var DefaultPageSizes = map[string]int{
+	"board.getComments":      100,
+	"board.getTopics":        100,
+	"friends.get":            5000,
+	"friends.getRequests":    1000,
+	"groups.get":             1000,
+	"groups.getMembers":      1000,
+	"groups.search":          1000,
+	"likes.getList":          1000,
+	"market.get":             200,
+	"newsfeed.getMentions":   50,
+	"photos.get":             1000,
+	"photos.getAlbums":       100,
+	"photos.getAll":          200,
+	"photos.getComments":     100,
+	"users.getFollowers":     1000,
+	"users.getSubscriptions": 200,
+	"users.search":           1000,
+	"video.get":              200,
+	"wall.get":               100,
+	"wall.getComments":       100,
+	"wall.getReposts":        1000,
+	"wall.search":            100,
+}
+
+// Параметры постраничной загрузки
+const (
+	OffsetParamKey = "offset"
+	CountParamKey  = "count"
+)
+
+// Постраничная загрузка списков методов с параметрами offset и count,
+// возвращающих ответ вида {"count": 10, "items": [...]}.
+// Шаблон запроса не изменяется: каждая страница запрашивается его копией.
+// После ошибки загрузку можно продолжить с того же смещения, повторно вызвав Next, All или Chan,
+// либо сохранить Offset() и продолжить с него в новом объекте через SetOffset()
+type Offset struct {
+	// Количество элементов на странице
+	PageSize int
+
+	executor *executor.Executor
+	request  *request.Request
+
+	mu     sync.Mutex
+	offset int
+	count  int
+	done   bool
+}
+
+// Создает постраничную загрузку по шаблону запроса.
+// Размер страницы берется из DefaultPageSizes по методу запроса
+func NewOffset(exec *executor.Executor, req *request.Request) *Offset {
+	pageSize, ok := DefaultPageSizes[req.GetMethod()]
+	if !ok {
+		pageSize = DefaultPageSize
+	}
+
+	return &Offset{
+		PageSize: pageSize,
+		executor: exec,
+		request:  req,
+		count:    -1,
+	}
+}
+
+// Загружает следующую страницу и сдвигает смещение.
+// Возвращает пустую страницу, если список закончился
+func (v *Offset) Next(ctx context.Context) (*Page, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.done {
+		return &Page{Count: v.count}, nil
+	}
+
+	req := v.request.Clone()
+	if req.GetParams() == nil {
+		req.Params(request.NewParams())
+	}
+	req.GetParams().Set(OffsetParamKey, strconv.Itoa(v.offset))
+	req.GetParams().Set(CountParamKey, strconv.Itoa(v.PageSize))
+
+	page, err := fetchPage(ctx, v.executor, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Смещение сдвигается на размер страницы, а не на количество элементов,
+	// потому что VK API может вернуть меньше элементов, пропустив удаленные
+	v.offset += v.PageSize
+	if page.Count >= 0 {
+		v.count = page.Count
+	}
+
+	if v.count >= 0 {
+		v.done = v.offset >= v.count
+	} else {
+		v.done = len(page.Items) == 0
+	}
+
+	return page, nil
+}
+
+// Сообщает, загружены ли все страницы
+func (v *Offset) Done() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.done
+}
+
+// Возвращает смещение следующей страницы
+func (v *Offset) Offset() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.offset
+}
+
+// Устанавливает смещение следующей страницы, например, для продолжения загрузки после перезапуска
+func (v *Offset) SetOffset(offset int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.offset = offset
+	v.done = false
+}
+
+// Возвращает общее количество элементов из последней загруженной страницы, -1 если оно неизвестно
+func (v *Offset) Count() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.count
+}
+
+// Возвращает ленивую последовательность элементов всех оставшихся страниц.
+//
+//	for item, err := range pages.All(ctx) { ... } // Go 1.23+
+func (v *Offset) All(ctx context.Context) Seq2[json.RawMessage, error] {
+	return all(ctx, v)
+}
+
+// Возвращает канал элементов всех оставшихся страниц
+func (v *Offset) Chan(ctx context.Context) <-chan Item {
+	return channel(ctx, v)
+}
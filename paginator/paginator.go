@@ -0,0 +1,117 @@
+// Пакет paginator реализует постраничную загрузку списков VK API через смещение (offset) и курсоры (start_from)
+package paginator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/jsonresponseparser"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Последовательность пар значений, совместимая по сигнатуре с iter.Seq2
+type Seq2[K, V any] func(yield func(K, V) bool)
+
+// Элемент списка, передаваемый через канал
+type Item struct {
+	Value json.RawMessage // Элемент списка в формате JSON
+	Err   error           // Ошибка загрузки страницы, после нее канал закрывается
+}
+
+// Страница списка
+type Page struct {
+	Count int               // Общее количество элементов списка из поля count, -1 если поля нет
+	Items []json.RawMessage // Элементы страницы
+	Body  []byte            // Тело ответа страницы
+}
+
+// Источник страниц списка
+type pager interface {
+	Next(ctx context.Context) (*Page, error)
+	Done() bool
+}
+
+// Возвращает ленивую последовательность элементов всех страниц.
+// При ошибке последовательность возвращает ее вторым значением и завершается
+func all(ctx context.Context, p pager) Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		for !p.Done() {
+			page, err := p.Next(ctx)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Возвращает канал с элементами всех страниц. Канал закрывается после последней страницы,
+// ошибки или завершения контекста
+func channel(ctx context.Context, p pager) <-chan Item {
+	items := make(chan Item)
+
+	go func() {
+		defer close(items)
+
+		all(ctx, p)(func(value json.RawMessage, err error) bool {
+			select {
+			case items <- Item{Value: value, Err: err}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return items
+}
+
+// Выполняет запрос страницы и разбирает поля count и items
+func fetchPage(ctx context.Context, exec *executor.Executor, req *request.Request) (*Page, error) {
+	res, err := exec.DoRequestCtx(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePage(res.Body())
+}
+
+// Разбирает страницу вида {"response": {"count": 10, "items": [...]}}
+func parsePage(body []byte) (*Page, error) {
+	page := &Page{
+		Count: -1,
+		Body:  body,
+	}
+
+	if count, err := jsonparser.GetInt(body, "response", "count"); err == nil {
+		page.Count = int(count)
+	}
+
+	items, dataType, _, err := jsonparser.Get(body, "response", "items")
+	if errors.Is(err, jsonparser.KeyPathNotFoundError) {
+		return page, nil
+	}
+
+	if err != nil || dataType != jsonparser.Array {
+		return nil, fmt.Errorf("page items not found in response: %s", body)
+	}
+
+	_, err = jsonparser.ArrayEach(items, func(value []byte, dataType jsonparser.ValueType, _ int, _ error) {
+		page.Items = append(page.Items, jsonresponseparser.RawJson(value, dataType))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse page items error: %w", err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,515 @@
+package paginator_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/paginator"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Транспорт, имитирующий список из total элементов с постраничной загрузкой через offset
+type listRoundTripper struct {
+	mu       sync.Mutex
+	total    int
+	failAt   int // Номер запроса, на котором вернуть ошибку VK API, начиная с 1
+	requests []string
+}
+
+func (v *listRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	v.requests = append(v.requests, req.PostForm.Encode())
+	requestNumber := len(v.requests)
+	v.mu.Unlock()
+
+	body := ""
+	if requestNumber == v.failAt {
+		body = `{"error":{"error_code":10,"error_msg":"Internal server error"}}`
+	} else {
+		offset, _ := strconv.Atoi(req.PostForm.Get("offset"))
+		count, _ := strconv.Atoi(req.PostForm.Get("count"))
+
+		items := []string{}
+		for i := offset; i < offset+count && i < v.total; i++ {
+			items = append(items, strconv.Itoa(i))
+		}
+		body = fmt.Sprintf(`{"response":{"count":%d,"items":[%s]}}`, v.total, strings.Join(items, ","))
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func newListExecutor(rt http.RoundTripper) *executor.Executor {
+	exec := executor.New()
+	exec.HttpClient = &http.Client{Transport: rt}
+	return exec
+}
+
+func collectItems(t *testing.T, seq paginator.Seq2[json.RawMessage, error]) ([]string, error) {
+	t.Helper()
+
+	var items []string
+	var seqErr error
+	seq(func(item json.RawMessage, err error) bool {
+		if err != nil {
+			seqErr = err
+			return false
+		}
+		items = append(items, string(item))
+		return true
+	})
+
+	return items, seqErr
+}
+
+func TestOffset(t *testing.T) {
+	newRequest := func() *request.Request {
+		req := request.New()
+		req.Method("groups.getMembers")
+		req.GetParams().Set("group_id", "1")
+		return req
+	}
+
+	t.Run("load all pages", func(t *testing.T) {
+		rt := &listRoundTripper{total: 25}
+		req := newRequest()
+
+		pages := paginator.NewOffset(newListExecutor(rt), req)
+		pages.PageSize = 10
+
+		items, err := collectItems(t, pages.All(context.Background()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(items) != 25 || items[0] != "0" || items[24] != "24" {
+			t.Errorf("unexpected items: %v", items)
+		}
+
+		if len(rt.requests) != 3 {
+			t.Errorf("expected 3 page requests, got %d", len(rt.requests))
+		}
+
+		if req.GetParams().Has(paginator.OffsetParamKey) {
+			t.Errorf("template request changed")
+		}
+
+		if !pages.Done() || pages.Count() != 25 {
+			t.Errorf("unexpected state: done %v, count %d", pages.Done(), pages.Count())
+		}
+	})
+
+	t.Run("default page size by method", func(t *testing.T) {
+		pages := paginator.NewOffset(executor.New(), newRequest())
+		if pages.PageSize != 1000 {
+			t.Errorf("expected page size 1000, got %d", pages.PageSize)
+		}
+	})
+
+	t.Run("resume after error", func(t *testing.T) {
+		rt := &listRoundTripper{total: 30, failAt: 2}
+
+		pages := paginator.NewOffset(newListExecutor(rt), newRequest())
+		pages.PageSize = 10
+
+		items, err := collectItems(t, pages.All(context.Background()))
+		if err == nil {
+			t.Fatal("expected api error")
+		}
+
+		if len(items) != 10 || pages.Offset() != 10 {
+			t.Errorf("unexpected state after error: items %v, offset %d", items, pages.Offset())
+		}
+
+		resumed := paginator.NewOffset(newListExecutor(rt), newRequest())
+		resumed.PageSize = 10
+		resumed.SetOffset(pages.Offset())
+
+		rest, err := collectItems(t, resumed.All(context.Background()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rest) != 20 || rest[0] != "10" {
+			t.Errorf("unexpected resumed items: %v", rest)
+		}
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		rt := &listRoundTripper{total: 15}
+
+		pages := paginator.NewOffset(newListExecutor(rt), newRequest())
+		pages.PageSize = 10
+
+		count := 0
+		for item := range pages.Chan(context.Background()) {
+			if item.Err != nil {
+				t.Fatal(item.Err)
+			}
+			count++
+		}
+
+		if count != 15 {
+			t.Errorf("expected 15 items, got %d", count)
+		}
+	})
+
+	t.Run("stop iteration early", func(t *testing.T) {
+		rt := &listRoundTripper{total: 100}
+
+		pages := paginator.NewOffset(newListExecutor(rt), newRequest())
+		pages.PageSize = 10
+
+		taken := 0
+		pages.All(context.Background())(func(item json.RawMessage, err error) bool {
+			taken++
+			return taken < 5
+		})
+
+		if len(rt.requests) != 1 {
+			t.Errorf("expected 1 page request, got %d", len(rt.requests))
+		}
+	})
+
+	t.Run("api error in channel", func(t *testing.T) {
+		rt := &listRoundTripper{total: 15, failAt: 1}
+
+		pages := paginator.NewOffset(newListExecutor(rt), newRequest())
+
+		var lastErr error
+		for item := range pages.Chan(context.Background()) {
+			lastErr = item.Err
+		}
+
+		if lastErr == nil || errors.Is(lastErr, context.Canceled) {
+			t.Errorf("expected api error, got %v", lastErr)
+		}
+	})
+}
+
+// Транспорт, имитирующий ленту из total элементов с курсорами
+type cursorRoundTripper struct {
+	total    int
+	failAt   int
+	requests []string
+}
+
+func (v *cursorRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	v.requests = append(v.requests, req.PostForm.Encode())
+
+	count, _ := strconv.Atoi(req.PostForm.Get("count"))
+	body := ""
+
+	switch {
+	case len(v.requests) == v.failAt:
+		body = `{"error":{"error_code":10,"error_msg":"Internal server error"}}`
+	case strings.HasSuffix(req.URL.Path, "wall.getComments"):
+		// start_comment_id включает сам комментарий в ответ
+		start, _ := strconv.Atoi(req.PostForm.Get("start_comment_id"))
+		items := []string{}
+		for id := start; id < start+count && id < v.total; id++ {
+			items = append(items, fmt.Sprintf(`{"id":%d}`, id))
+		}
+		body = fmt.Sprintf(`{"response":{"count":%d,"items":[%s]}}`, v.total, strings.Join(items, ","))
+	default:
+		start := 0
+		if startFrom := req.PostForm.Get("start_from"); startFrom != "" {
+			start, _ = strconv.Atoi(strings.TrimPrefix(startFrom, "c"))
+		}
+		items := []string{}
+		for i := start; i < start+count && i < v.total; i++ {
+			items = append(items, strconv.Itoa(i))
+		}
+		nextFrom := ""
+		if start+count < v.total {
+			nextFrom = fmt.Sprintf("c%d", start+count)
+		}
+		body = fmt.Sprintf(`{"response":{"items":[%s],"next_from":"%s"}}`, strings.Join(items, ","), nextFrom)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestCursor(t *testing.T) {
+	newRequest := func(method string) *request.Request {
+		req := request.New()
+		req.Method(method)
+		return req
+	}
+
+	t.Run("next from cursor", func(t *testing.T) {
+		rt := &cursorRoundTripper{total: 250}
+
+		pages := paginator.NewCursor(newListExecutor(rt), newRequest("newsfeed.get"))
+
+		items, err := collectItems(t, pages.All(context.Background()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(items) != 250 || items[249] != "249" {
+			t.Errorf("unexpected items count: %d", len(items))
+		}
+
+		if len(rt.requests) != 3 || !pages.Done() || pages.Cursor() != "" {
+			t.Errorf("unexpected state: requests %d, done %v, cursor %q", len(rt.requests), pages.Done(), pages.Cursor())
+		}
+	})
+
+	t.Run("checkpoint and resume", func(t *testing.T) {
+		rt := &cursorRoundTripper{total: 250, failAt: 2}
+
+		pages := paginator.NewCursor(newListExecutor(rt), newRequest("newsfeed.get"))
+
+		items, err := collectItems(t, pages.All(context.Background()))
+		if err == nil {
+			t.Fatal("expected api error")
+		}
+
+		checkpoint := pages.Cursor()
+		if len(items) != 100 || checkpoint != "c100" {
+			t.Fatalf("unexpected state after error: %d items, cursor %q", len(items), checkpoint)
+		}
+
+		resumed := paginator.NewCursor(newListExecutor(rt), newRequest("newsfeed.get"))
+		resumed.SetCursor(checkpoint)
+
+		rest, err := collectItems(t, resumed.All(context.Background()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rest) != 150 || rest[0] != "100" {
+			t.Errorf("unexpected resumed items: %d", len(rest))
+		}
+	})
+
+	t.Run("escaped cursor is unescaped", func(t *testing.T) {
+		page := &paginator.Page{
+			Body:  []byte(`{"response":{"items":[{"id":"5\/abc"}],"next_from":"1\/abc&d"}}`),
+			Items: []json.RawMessage{json.RawMessage(`{"id":"5\/abc"}`)},
+		}
+
+		if cursor := paginator.NextFromCursor("next_from")(page); cursor != "1/abc&d" {
+			t.Errorf("unexpected next_from cursor: %q", cursor)
+		}
+
+		if cursor := paginator.LastItemCursor("id")(page); cursor != "5/abc" {
+			t.Errorf("unexpected last item cursor: %q", cursor)
+		}
+	})
+
+	t.Run("last item cursor skips duplicates", func(t *testing.T) {
+		rt := &cursorRoundTripper{total: 250}
+
+		pages := paginator.NewCursor(newListExecutor(rt), newRequest("wall.getComments"))
+
+		items, err := collectItems(t, pages.All(context.Background()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen := map[string]bool{}
+		for _, item := range items {
+			if seen[item] {
+				t.Fatalf("duplicate item %s", item)
+			}
+			seen[item] = true
+		}
+
+		if len(items) != 250 {
+			t.Errorf("expected 250 comments, got %d", len(items))
+		}
+	})
+}
+
+// Транспорт, имитирующий список участников группы с запросами через execute.
+// Если задан barrier, первые запросы execute ждут, пока запросы execute не придут со всеми barrier токенами
+type executeListRoundTripper struct {
+	mu       sync.Mutex
+	total    int
+	tokens   map[string]int
+	executes int
+
+	barrier       int
+	executeTokens map[string]bool
+	allTokens     chan struct{}
+}
+
+// Ждет, пока запросы execute придут со всеми токенами барьера
+func (v *executeListRoundTripper) waitTokens(token string) {
+	v.mu.Lock()
+	if v.barrier == 0 {
+		v.mu.Unlock()
+		return
+	}
+
+	if v.allTokens == nil {
+		v.allTokens = make(chan struct{})
+		v.executeTokens = map[string]bool{}
+	}
+
+	allTokens := v.allTokens
+	if !v.executeTokens[token] {
+		v.executeTokens[token] = true
+		if len(v.executeTokens) == v.barrier {
+			close(allTokens)
+		}
+	}
+	v.mu.Unlock()
+
+	select {
+	case <-allTokens:
+	case <-time.After(5 * time.Second):
+	}
+}
+
+var executeCallRegexp = regexp.MustCompile(`"count":"(\d+)".*?"offset":"(\d+)"`)
+
+func (v *executeListRoundTripper) page(offset, count int) string {
+	items := []string{}
+	for i := offset; i < offset+count && i < v.total; i++ {
+		// Каждая страница повторяет последний элемент предыдущей, как при сдвиге списка
+		if i == offset && i > 0 {
+			items = append(items, fmt.Sprintf(`{"id":%d}`, i-1))
+		}
+		items = append(items, fmt.Sprintf(`{"id":%d}`, i))
+	}
+	return fmt.Sprintf(`{"count":%d,"items":[%s]}`, v.total, strings.Join(items, ","))
+}
+
+func (v *executeListRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	if v.tokens == nil {
+		v.tokens = map[string]int{}
+	}
+	v.tokens[req.PostForm.Get("access_token")]++
+	v.mu.Unlock()
+
+	body := ""
+	if strings.HasSuffix(req.URL.Path, "/execute") {
+		v.mu.Lock()
+		v.executes++
+		v.mu.Unlock()
+
+		v.waitTokens(req.PostForm.Get("access_token"))
+
+		slots := []string{}
+		for _, call := range strings.Split(req.PostForm.Get("code"), "API.")[1:] {
+			match := executeCallRegexp.FindStringSubmatch(call)
+			count, _ := strconv.Atoi(match[1])
+			offset, _ := strconv.Atoi(match[2])
+			slots = append(slots, v.page(offset, count))
+		}
+		body = `{"response":[` + strings.Join(slots, ",") + `]}`
+	} else {
+		count, _ := strconv.Atoi(req.PostForm.Get("count"))
+		body = `{"response":` + v.page(0, count) + `}`
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestCollector(t *testing.T) {
+	req := request.New()
+	req.Method("groups.getMembers")
+	req.GetParams().Set("group_id", "1")
+
+	t.Run("collect list in order across tokens", func(t *testing.T) {
+		rt := &executeListRoundTripper{total: 1234, barrier: 3}
+
+		collector := paginator.NewCollector(newListExecutor(rt), "a", "b", "c")
+		collector.PageSize = 10
+		collector.PagesPerExecute = 5
+
+		var progress []paginator.Progress
+		collector.Progress = func(p paginator.Progress) {
+			progress = append(progress, p)
+		}
+
+		items, err := collector.Collect(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(items) != 1234 {
+			t.Fatalf("expected 1234 unique items, got %d", len(items))
+		}
+
+		for i, item := range items {
+			if string(item) != fmt.Sprintf(`{"id":%d}`, i) {
+				t.Fatalf("item %d out of order: %s", i, item)
+			}
+		}
+
+		// 123 страницы после первой по 5 в execute
+		if rt.executes != 25 {
+			t.Errorf("expected 25 execute requests, got %d", rt.executes)
+		}
+
+		if len(rt.tokens) != 3 {
+			t.Errorf("expected requests with 3 tokens, got %v", rt.tokens)
+		}
+
+		last := progress[len(progress)-1]
+		if last.ChunksDone != last.Chunks || last.Total != 1234 {
+			t.Errorf("unexpected final progress: %+v", last)
+		}
+	})
+
+	t.Run("api error stops collecting", func(t *testing.T) {
+		exec := newListExecutor(&executeListRoundTripper{total: 100})
+		exec.ApiResponseHook(func(next executor.ApiResponseNextHook, res response.Response) error {
+			if executor.GetRequest(res.Context()).GetMethod() == "execute" {
+				return errors.New("execute failed")
+			}
+			return next(res)
+		})
+
+		collector := paginator.NewCollector(exec)
+		collector.PageSize = 10
+
+		if _, err := collector.Collect(context.Background(), req); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
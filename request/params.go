@@ -58,10 +58,35 @@ func (v Params) Del(key string) {
 	v.params.Del(key)
 }
 
-// Сериализует параметры в строку, предварительно вызывая метод params.ComposeValues()
+// Сериализует параметры в строку.
+// Если задана настройка RemoveBlanks, ключи с пустыми значениями не попадают в результат,
+// при этом сами параметры не изменяются, поэтому метод можно вызывать из нескольких горутин
 func (v *Params) String() string {
-	v.ComposeValues()
-	return v.params.Encode()
+	if !v.RemoveBlanks {
+		return v.params.Encode()
+	}
+	return v.Values().Encode()
+}
+
+// Возвращает копию значений параметров в том виде, в котором они будут отправлены
+func (v *Params) Values() url.Values {
+	values := cloneValues(v.params)
+	if v.RemoveBlanks {
+		v.clearBlanks(values)
+	}
+	return values
+}
+
+// Возвращает независимую копию параметров
+func (v *Params) Clone() *Params {
+	if v == nil {
+		return nil
+	}
+
+	return &Params{
+		params:       cloneValues(v.params),
+		RemoveBlanks: v.RemoveBlanks,
+	}
 }
 
 // Устанавливает токен доступа
@@ -129,3 +154,12 @@ func (v *Params) clearBlanks(params url.Values) {
 		}
 	}
 }
+
+// Возвращает глубокую копию url значений
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for key, val := range values {
+		clone[key] = append([]string(nil), val...)
+	}
+	return clone
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ciricc/vkapiexecutor/request"
@@ -236,4 +237,55 @@ func TestParams(t *testing.T) {
 			t.Errorf("not changed key for device id")
 		}
 	})
+
+	t.Run("string does not change params with remove blanks", func(t *testing.T) {
+		params := request.NewParams()
+		params.RemoveBlanks = true
+		params.Set("key", "")
+
+		if strings.Contains(params.String(), "key=") {
+			t.Errorf("blank key serialized: %q", params.String())
+		}
+
+		if !params.Has("key") {
+			t.Errorf("blank key removed from original params")
+		}
+	})
+
+	t.Run("clone params", func(t *testing.T) {
+		params := request.NewParams()
+		params.AccessToken("abc")
+
+		clone := params.Clone()
+		clone.AccessToken("def")
+
+		if params.GetAccessToken() != "abc" || clone.GetAccessToken() != "def" {
+			t.Errorf("params shared with clone")
+		}
+	})
+
+	t.Run("redacted params hide secrets", func(t *testing.T) {
+		params := request.NewParams()
+		params.AccessToken("secret_token")
+		params.Set("client_secret", "secret_value")
+		params.Set("user_ids", "1")
+
+		redacted := params.RedactedString()
+		if strings.Contains(redacted, "secret_") {
+			t.Errorf("secret leaked: %q", redacted)
+		}
+
+		if !strings.Contains(redacted, "user_ids=1") || !strings.Contains(redacted, "access_token="+request.RedactedValue) {
+			t.Errorf("unexpected redacted params: %q", redacted)
+		}
+
+		if params.GetAccessToken() != "secret_token" {
+			t.Errorf("redaction changed original params")
+		}
+
+		logged := params.LogValue().String()
+		if strings.Contains(logged, "secret_") {
+			t.Errorf("secret leaked in log value: %q", logged)
+		}
+	})
 }
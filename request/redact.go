@@ -0,0 +1,96 @@
+package request
+
+import (
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Значение, которым заменяются секретные параметры в логах
+const RedactedValue = "[REDACTED]"
+
+// Ключи параметров, значения которых не попадают в логи
+var SecretParamKeys = map[string]bool{
+	AccessTokenParamKey: true,
+	AnonymousTokenKey:   true,
+	"client_secret":     true,
+	"captcha_key":       true,
+	"sig":               true,
+	"password":          true,
+}
+
+// Возвращает копию значений параметров, в которой значения секретных ключей заменены на RedactedValue
+func (v *Params) Redacted() url.Values {
+	if v == nil {
+		return url.Values{}
+	}
+
+	values := v.Values()
+	for key, vals := range values {
+		if !SecretParamKeys[key] {
+			continue
+		}
+		for i := range vals {
+			vals[i] = RedactedValue
+		}
+	}
+
+	return values
+}
+
+// Сериализует параметры в строку для логов, скрывая значения секретных ключей
+func (v *Params) RedactedString() string {
+	values := v.Redacted()
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		for _, val := range values[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			if val == RedactedValue {
+				b.WriteString(val)
+			} else {
+				b.WriteString(url.QueryEscape(val))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Реализует slog.LogValuer: параметры логируются группой, значения секретных ключей скрываются
+func (v *Params) LogValue() slog.Value {
+	values := v.Redacted()
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.String(key, strings.Join(values[key], ",")))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Реализует slog.LogValuer: запрос логируется группой из URL пути к API, метода и параметров без секретов
+func (v *Request) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("url", v.GetBaseUrl()),
+		slog.String("method", v.GetMethod()),
+		slog.Attr{Key: "params", Value: v.GetParams().LogValue()},
+	)
+}
@@ -1,12 +1,15 @@
 package request
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"sync"
 )
 
 // URL путь к VK API
@@ -15,13 +18,17 @@ var DefaultBaseRequestUrl = "https://api.vk.com/method/"
 // Тип содержимого запроса по умолчанию
 const DefaultContentTypeHeaderValue = "application/x-www-form-urlencoded"
 
-// Объект запроса к API ВКонткте
+// Объект запроса к API ВКонткте.
+// Запрос можно использовать как шаблон из нескольких горутин одновременно:
+// каждый вызов HttpRequestPost и HttpRequestGet возвращает независимый HTTP запрос
+// со снимком заголовков и параметров. Изменять параметры и заголовки, полученные через GetParams и GetHeaders,
+// во время выполнения запросов нельзя - для этого используйте копию запроса из Clone
 type Request struct {
-	method          string        // Метод VK API
-	params          *Params       // Параметры запроса
-	headers         http.Header   // HTTP заголовки запроса. По умолчанию в запросе есть один загловок - Content-Type, его изменить нельзя
-	getHttpRequest  *http.Request // HTTP запрос для GET метода
-	postHttpRequest *http.Request // HTTP запрос для POST метода
+	mu      sync.RWMutex // Защищает поля запроса от одновременного изменения и чтения
+	method  string       // Метод VK API
+	baseUrl string       // URL путь к VK API. Если не задан, используется request.DefaultBaseRequestUrl
+	params  *Params      // Параметры запроса
+	headers http.Header  // HTTP заголовки запроса. По умолчанию в запросе есть один загловок - Content-Type, его изменить нельзя
 }
 
 // Создает новый API запрос
@@ -34,70 +41,134 @@ func New() *Request {
 	return r
 }
 
+// Возвращает независимую копию запроса с копиями заголовков и параметров
+func (v *Request) Clone() *Request {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return &Request{
+		method:  v.method,
+		baseUrl: v.baseUrl,
+		params:  v.params.Clone(),
+		headers: v.headers.Clone(),
+	}
+}
+
 // Устанавливает метод VK API и возвращает копию новый запрос
 func (v *Request) Method(methodName string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	v.method = methodName
 }
 
 // Method возвращает текущий метод запроса
 func (v *Request) GetMethod() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
 	return v.method
 }
 
+// Устанавливает URL путь к VK API для этого запроса, например "https://api.vk.ru/method/".
+// Пустая строка возвращает использование request.DefaultBaseRequestUrl
+func (v *Request) BaseUrl(baseUrl string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.baseUrl = baseUrl
+}
+
+// Возвращает URL путь к VK API, по которому будет отправлен запрос
+func (v *Request) GetBaseUrl() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.getBaseUrl()
+}
+
 // SetParams устанавливает параметры запроса. Глобальные значения при этом не перезаписываются
 func (v *Request) Params(params *Params) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	v.params = params
 }
 
 // Params возвращает текущий объект параетров
 func (v *Request) GetParams() *Params {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
 	return v.params
 }
 
-// Headers возвращает копию текущих заголовков запроса
-func (v *Request) GetHeaders() http.Header {
-	if v.headers == nil {
-		v.headers = make(http.Header)
+// Возвращает токен доступа запроса или, если его нет, анонимный токен.
+// Для nil запроса и запроса без параметров возвращает пустую строку
+func (v *Request) GetToken() string {
+	if v == nil {
+		return ""
 	}
-	return v.headers
-}
 
-// Возвращает и создает, если нет, http запрос для POST метода
-func (v *Request) getHttpRequestPost() *http.Request {
-	if v.postHttpRequest == nil {
-		v.postHttpRequest = &http.Request{
-			Method: "POST",
-		}
+	params := v.GetParams()
+	if params == nil {
+		return ""
 	}
-	return v.postHttpRequest
-}
 
-// Возвращает и создает, если нет, http запрос для GET метода
-func (v *Request) getHttpRequestGet() *http.Request {
-	if v.getHttpRequest == nil {
-		v.getHttpRequest = &http.Request{
-			Method: "GET",
-		}
+	if token := params.GetAccessToken(); token != "" {
+		return token
 	}
-	return v.getHttpRequest
+
+	return params.GetAnonymousToken()
+}
+
+// Headers возвращает копию текущих заголовков запроса
+func (v *Request) GetHeaders() http.Header {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.getHeaders()
 }
 
 // Устанавливает заголовки, полностью перезаписывает текущие заголовки
 // Заголовок Content-Type при этом не изменяется, так как Request гарантирует одинаковый формат содержимого
 func (v *Request) Headers(headers http.Header) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	v.headers = headers
 	v.setContentTypeHeader()
 }
 
-// Сериализирует объект запроса в строку для удобного отображения в логах
+// Сериализирует объект запроса в строку для удобного отображения в логах.
+// Значения секретных параметров (токенов, client_secret, captcha_key, sig) скрываются
 func (v *Request) String() string {
-	return fmt.Sprintf("url: %q\nmethod: %q\nheaders: %v\nparams: %q", DefaultBaseRequestUrl, v.GetMethod(), v.GetHeaders(), v.GetParams())
+	return fmt.Sprintf("url: %q\nmethod: %q\nheaders: %v\nparams: %q", v.GetBaseUrl(), v.GetMethod(), v.GetHeaders(), v.GetParams().RedactedString())
+}
+
+// Возвращает отпечаток запроса: хеш URL пути к API, метода и отсортированных параметров.
+// Заголовки не учитываются. Если includeToken равен false, токен доступа и анонимный токен
+// не влияют на отпечаток
+func (v *Request) Fingerprint(includeToken bool) string {
+	values := url.Values{}
+	if params := v.GetParams(); params != nil {
+		values = params.Values()
+	}
+
+	if !includeToken {
+		values.Del(AccessTokenParamKey)
+		values.Del(AnonymousTokenKey)
+	}
+
+	hash := sha256.New()
+	io.WriteString(hash, v.GetBaseUrl())
+	hash.Write([]byte{0})
+	io.WriteString(hash, v.GetMethod())
+	hash.Write([]byte{0})
+	io.WriteString(hash, values.Encode())
+
+	return hex.EncodeToString(hash.Sum(nil))
 }
 
 // Расширяет текущие заголовки.
 // При этом, значение ключа будет перезаписано, если оно уже есть.
 func (v *Request) AppendHeaders(headers http.Header) {
-	requestHeaders := v.GetHeaders()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	requestHeaders := v.getHeaders()
 
 	for key, val := range headers {
 		requestHeaders[key] = val
@@ -109,36 +180,61 @@ func (v *Request) AppendHeaders(headers http.Header) {
 /*
 Возвращает URL запроса без параметров.
 
-	Метод использует значение переменной request.DefaultBaseRequestUrl
+	Метод использует значение request.GetBaseUrl(), по умолчанию это переменная request.DefaultBaseRequestUrl
 */
 func (v *Request) GetRequestUrl() (*url.URL, error) {
-	baseUrl, err := url.Parse(DefaultBaseRequestUrl)
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return MethodUrl(v.getBaseUrl(), v.method)
+}
+
+// Возвращает URL метода VK API относительно базового URL
+func MethodUrl(baseUrl string, method string) (*url.URL, error) {
+	requestUrl, err := url.Parse(baseUrl)
 	if err != nil {
 		return nil, fmt.Errorf("parse base url variable error: %w", err)
 	}
-	baseUrl.Path = path.Join(baseUrl.Path, "/", v.method)
-	return baseUrl, nil
+	requestUrl.Path = path.Join(requestUrl.Path, "/", method)
+	return requestUrl, nil
 }
 
-// Возвращает объект запроса для POST метода
+// Возвращает новый объект запроса для POST метода
 func (v *Request) HttpRequestPost() (*http.Request, error) {
 	return v.buildHttpRequest("POST")
 }
 
-// Возвращает объект запроса для GET метода
+// Возвращает новый объект запроса для GET метода
 func (v *Request) HttpRequestGet() (*http.Request, error) {
 	return v.buildHttpRequest("GET")
 }
 
+// Возвращает URL путь к VK API без блокировки
+func (v *Request) getBaseUrl() string {
+	if v.baseUrl == "" {
+		return DefaultBaseRequestUrl
+	}
+	return v.baseUrl
+}
+
+// Возвращает заголовки без блокировки, создавая их при необходимости
+func (v *Request) getHeaders() http.Header {
+	if v.headers == nil {
+		v.headers = make(http.Header)
+	}
+	return v.headers
+}
+
 // Устанавливает заголовок content-type
 func (v *Request) setContentTypeHeader() {
-	if v.GetHeaders().Get("content-type") != DefaultContentTypeHeaderValue {
-		v.GetHeaders().Set("Content-Type", DefaultContentTypeHeaderValue)
+	if v.getHeaders().Get("content-type") != DefaultContentTypeHeaderValue {
+		v.getHeaders().Set("Content-Type", DefaultContentTypeHeaderValue)
 	}
 }
 
 // Возвращает объект http.Request данного API запроса стандартной библиотеки net/http
 // По умолчанию все запросы используют заголовок Content-Type: application/x-www-form-urlencoded
+// Каждый вызов создает новый HTTP запрос с копией заголовков и параметров,
+// поэтому результат можно изменять и отправлять независимо от других вызовов
 // request.HttpRequest("GET")
 // request.GttpRequest("POST")
 func (v *Request) buildHttpRequest(method string) (*http.Request, error) {
@@ -148,22 +244,17 @@ func (v *Request) buildHttpRequest(method string) (*http.Request, error) {
 		return nil, fmt.Errorf("build http request url error: %w", err)
 	}
 
-	var req *http.Request
-
-	if method == "GET" {
-		req = v.getHttpRequestGet()
-	} else {
-		req = v.getHttpRequestPost()
+	req := &http.Request{
+		Method: method,
+		URL:    requestUrl,
+		Header: v.GetHeaders().Clone(),
 	}
 
-	req.URL = requestUrl
-	req.Header = v.GetHeaders()
-
-	if v.params != nil {
+	if params := v.GetParams(); params != nil {
 		if method == "GET" {
-			req.URL.RawQuery = v.params.String()
+			req.URL.RawQuery = params.String()
 		} else {
-			req.Body = io.NopCloser(strings.NewReader(v.params.String()))
+			req.Body = io.NopCloser(strings.NewReader(params.String()))
 		}
 	}
 
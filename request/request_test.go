@@ -42,6 +42,38 @@ func TestRequest(t *testing.T) {
 		}
 	})
 
+	t.Run("serialized request hides tokens", func(t *testing.T) {
+		req := request.New()
+		req.GetParams().AccessToken("secret_token")
+		req.GetParams().AnonymousToken("secret_anonymous")
+
+		if strings.Contains(req.String(), "secret_") {
+			t.Errorf("token leaked: %s", req.String())
+		}
+
+		if strings.Contains(req.LogValue().String(), "secret_") {
+			t.Errorf("token leaked in log value: %s", req.LogValue())
+		}
+	})
+
+	t.Run("request token", func(t *testing.T) {
+		req := request.New()
+		req.GetParams().AnonymousToken("anonymous")
+		if req.GetToken() != "anonymous" {
+			t.Errorf("expected anonymous token, got %q", req.GetToken())
+		}
+
+		req.GetParams().AccessToken("access")
+		if req.GetToken() != "access" {
+			t.Errorf("expected access token, got %q", req.GetToken())
+		}
+
+		var nilReq *request.Request
+		if nilReq.GetToken() != "" {
+			t.Errorf("expected empty token of nil request")
+		}
+	})
+
 	t.Run("request url correctly built", func(t *testing.T) {
 		req := request.New()
 		req.Method("users.get")
@@ -80,6 +112,27 @@ func TestRequest(t *testing.T) {
 		}
 	})
 
+	t.Run("request url built with custom base url", func(t *testing.T) {
+		req := request.New()
+		req.Method("users.get")
+		req.BaseUrl("http://localhost:8080/vk/method/")
+
+		url, err := req.GetRequestUrl()
+		if err != nil {
+			t.Error(err)
+		}
+
+		expectedUrl := "http://localhost:8080/vk/method/users.get"
+		if url.String() != expectedUrl {
+			t.Errorf("expected url: %q\ngot: %q", expectedUrl, url)
+		}
+
+		req.BaseUrl("")
+		if req.GetBaseUrl() != request.DefaultBaseRequestUrl {
+			t.Errorf("expected default base url, got: %q", req.GetBaseUrl())
+		}
+	})
+
 	t.Run("set custom request headers", func(t *testing.T) {
 		req := request.New()
 		expectedUserAgent := "VKAndroidApp/5.17-2625 (Android 7.1.1; SDK 25; armeabi-v7a; samsung SM-G977N; ru; 1600x900)"
@@ -282,7 +335,7 @@ func TestRequest(t *testing.T) {
 		wg.Wait()
 	})
 
-	t.Run("same get requests pointers", func(t *testing.T) {
+	t.Run("independent get requests", func(t *testing.T) {
 		req := request.New()
 
 		httpReq1, err := req.HttpRequestGet()
@@ -295,13 +348,17 @@ func TestRequest(t *testing.T) {
 			t.Error(err)
 		}
 
-		if httpReq1 != httpReq2 {
-			t.Errorf("different requests: %v, %v", httpReq1, httpReq2)
+		if httpReq1 == httpReq2 || httpReq1.URL == httpReq2.URL {
+			t.Errorf("same requests: %v, %v", httpReq1, httpReq2)
 		}
 
+		httpReq1.Header.Set("User-Agent", "1")
+		if httpReq2.Header.Get("User-Agent") != "" || req.GetHeaders().Get("User-Agent") != "" {
+			t.Errorf("headers shared between requests")
+		}
 	})
 
-	t.Run("same post requests pointers", func(t *testing.T) {
+	t.Run("independent post requests", func(t *testing.T) {
 		req := request.New()
 
 		httpReq1, err := req.HttpRequestPost()
@@ -314,8 +371,51 @@ func TestRequest(t *testing.T) {
 			t.Error(err)
 		}
 
-		if httpReq1 != httpReq2 {
-			t.Errorf("different requests: %v, %v", httpReq1, httpReq2)
+		if httpReq1 == httpReq2 {
+			t.Errorf("same requests: %v, %v", httpReq1, httpReq2)
+		}
+
+		body1, _ := io.ReadAll(httpReq1.Body)
+		body2, _ := io.ReadAll(httpReq2.Body)
+		if string(body1) != string(body2) || len(body1) == 0 {
+			t.Errorf("different request bodies: %q, %q", body1, body2)
+		}
+	})
+
+	t.Run("params snapshot in built request", func(t *testing.T) {
+		req := request.New()
+		req.Method("users.get")
+		req.GetParams().Set("user_ids", "1")
+
+		httpReq, err := req.HttpRequestGet()
+		if err != nil {
+			t.Error(err)
+		}
+
+		req.GetParams().Set("user_ids", "2")
+
+		if httpReq.URL.Query().Get("user_ids") != "1" {
+			t.Errorf("built request changed after params change: %v", httpReq.URL)
+		}
+	})
+
+	t.Run("clone request", func(t *testing.T) {
+		req := request.New()
+		req.Method("users.get")
+		req.BaseUrl("http://localhost/method/")
+		req.GetParams().Set("user_ids", "1")
+
+		clone := req.Clone()
+		clone.Method("groups.getById")
+		clone.GetParams().Set("user_ids", "2")
+		clone.AppendHeaders(http.Header{"User-Agent": {"1"}})
+
+		if req.GetMethod() != "users.get" || req.GetParams().Get("user_ids") != "1" || req.GetHeaders().Get("User-Agent") != "" {
+			t.Errorf("original request changed by clone: %s", req)
+		}
+
+		if clone.GetBaseUrl() != req.GetBaseUrl() {
+			t.Errorf("base url not cloned: %q", clone.GetBaseUrl())
 		}
 	})
 }
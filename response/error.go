@@ -1,5 +1,7 @@
 package response
 
+import "net/url"
+
 // Объект ошибки, полученной в теле ответа HTTP запроса к API
 // Обрабатывает только ошибки, относящиеся к выполнению API метода
 type Error struct {
@@ -9,6 +11,16 @@ type Error struct {
 	CaptchaImg  string
 	RedirectUri string
 	Method      string
+
+	Subcode          int        // Уточняющий код ошибки error_subcode
+	Text             string     // Текст ошибки для показа пользователю error_text
+	RequestParams    url.Values // Параметры запроса, которые вернул VK API в request_params
+	CaptchaTs        float64    // Время создания капчи captcha_ts
+	CaptchaAttempt   int        // Номер попытки ввода капчи captcha_attempt
+	IsRefreshEnabled bool       // Можно ли обновить изображение капчи is_refresh_enabled
+	ConfirmationText string     // Текст подтверждения действия confirmation_text (ошибка 24)
+	ViewUrl          string     // Адрес страницы для пользователя view_url
+	Raw              []byte     // Исходный JSON объект ошибки, включая поля, которые не разбираются
 }
 
 func NewError(message string, intCode int) *Error {
@@ -27,3 +39,14 @@ func (v *Error) Error() string {
 func (v *Error) IntCode() int {
 	return v.intCode
 }
+
+// Возвращает типизированный код ошибки
+func (v *Error) Code() ErrorCode {
+	return ErrorCode(v.intCode)
+}
+
+// Сравнивает ошибки по коду, чтобы работал errors.Is(err, response.ErrAuthFailed)
+func (v *Error) Is(target error) bool {
+	targetErr, ok := target.(*Error)
+	return ok && targetErr.intCode != 0 && targetErr.intCode == v.intCode
+}
@@ -0,0 +1,132 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+)
+
+//go:generate go run gen_error_codes.go
+
+// Код ошибки VK API.
+// Константы кодов, ошибки для errors.Is() и описания генерируются из error_codes.json.
+// Каталог содержит общие ошибки VK API и ошибки основных разделов (стена, фотографии, видео, сообщения, сообщества и т.д).
+// Для кодов, которых нет в каталоге, Known() возвращает false, а Error.Code() и errors.Is() работают как обычно
+type ErrorCode int
+
+// Категории ошибок
+type errorCategory uint8
+
+const (
+	categoryAuth errorCategory = 1 << iota
+	categoryPermission
+	categoryRateLimit
+	categoryTemporary
+	categoryCaptcha
+	categoryValidation
+)
+
+// Описание кода ошибки
+type errorCodeInfo struct {
+	name       string
+	en         string
+	ru         string
+	categories errorCategory
+}
+
+// Возвращает имя кода ошибки, например AuthFailed
+func (c ErrorCode) String() string {
+	if info, ok := errorCodes[c]; ok {
+		return info.name
+	}
+	return fmt.Sprintf("ErrorCode(%d)", int(c))
+}
+
+// Возвращает описание ошибки на русском языке
+func (c ErrorCode) Description() string {
+	return errorCodes[c].ru
+}
+
+// Возвращает описание ошибки на английском языке
+func (c ErrorCode) DescriptionEn() string {
+	return errorCodes[c].en
+}
+
+// Сообщает, известен ли код ошибки
+func (c ErrorCode) Known() bool {
+	_, ok := errorCodes[c]
+	return ok
+}
+
+// Ошибка авторизации: недействительный токен или подпись
+func (c ErrorCode) IsAuth() bool {
+	return c.is(categoryAuth)
+}
+
+// Недостаточно прав или доступ к объекту запрещен
+func (c ErrorCode) IsPermission() bool {
+	return c.is(categoryPermission)
+}
+
+// Превышен лимит запросов или однотипных действий
+func (c ErrorCode) IsRateLimit() bool {
+	return c.is(categoryRateLimit)
+}
+
+// Временная ошибка, после которой запрос можно повторить
+func (c ErrorCode) IsTemporary() bool {
+	return c.is(categoryTemporary)
+}
+
+// Требуется ввод капчи
+func (c ErrorCode) IsCaptcha() bool {
+	return c.is(categoryCaptcha)
+}
+
+// Требуется проверка или подтверждение действия пользователем
+func (c ErrorCode) IsValidation() bool {
+	return c.is(categoryValidation)
+}
+
+func (c ErrorCode) is(category errorCategory) bool {
+	return errorCodes[c].categories&category != 0
+}
+
+// Возвращает код ошибки VK API из цепочки ошибок или 0, если ошибки VK API в ней нет.
+// Ошибки вызовов внутри execute (response.ExecuteErrors) не учитываются
+func CodeOf(err error) ErrorCode {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code()
+	}
+	return 0
+}
+
+// Сообщает, является ли err ошибкой авторизации VK API
+func IsAuth(err error) bool {
+	return CodeOf(err).IsAuth()
+}
+
+// Сообщает, является ли err ошибкой прав доступа VK API
+func IsPermission(err error) bool {
+	return CodeOf(err).IsPermission()
+}
+
+// Сообщает, является ли err ошибкой превышения лимитов VK API
+func IsRateLimit(err error) bool {
+	return CodeOf(err).IsRateLimit()
+}
+
+// Сообщает, является ли err временной ошибкой VK API
+func IsTemporary(err error) bool {
+	return CodeOf(err).IsTemporary()
+}
+
+// Сообщает, требует ли ошибка VK API ввода капчи
+func IsCaptcha(err error) bool {
+	return CodeOf(err).IsCaptcha()
+}
+
+// Сообщает, требует ли ошибка VK API проверки пользователя
+func IsValidation(err error) bool {
+	return CodeOf(err).IsValidation()
+}
@@ -0,0 +1,97 @@
+package response_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+func TestErrorCode(t *testing.T) {
+	t.Run("errors.Is matches by code", func(t *testing.T) {
+		err := fmt.Errorf("request failed: %w", response.NewError("User authorization failed: invalid access_token (4).", 5))
+
+		if !errors.Is(err, response.ErrAuthFailed) {
+			t.Errorf("expected ErrAuthFailed")
+		}
+
+		if errors.Is(err, response.ErrAccessDenied) {
+			t.Errorf("unexpected ErrAccessDenied")
+		}
+
+		if response.CodeOf(err) != response.ErrorCodeAuthFailed {
+			t.Errorf("unexpected code: %s", response.CodeOf(err))
+		}
+	})
+
+	t.Run("execute errors", func(t *testing.T) {
+		err := response.NewExecuteErrors([]*response.Error{
+			response.NewError("Access denied", 15),
+		})
+
+		if !errors.Is(err, response.ErrAccessDenied) {
+			t.Errorf("expected access denied in execute errors")
+		}
+
+		var apiErr *response.Error
+		if errors.As(err, &apiErr) || response.IsPermission(err) || response.CodeOf(err) != 0 {
+			t.Errorf("execute errors must not be matched as an error of the whole request")
+		}
+	})
+
+	t.Run("categories", func(t *testing.T) {
+		cases := []struct {
+			err   error
+			check func(error) bool
+			name  string
+		}{
+			{response.NewError("", 5), response.IsAuth, "auth"},
+			{response.NewError("", 15), response.IsPermission, "permission"},
+			{response.NewError("", 6), response.IsRateLimit, "rate limit"},
+			{response.NewError("", 10), response.IsTemporary, "temporary"},
+			{response.NewError("", 14), response.IsCaptcha, "captcha"},
+			{response.NewError("", 17), response.IsValidation, "validation"},
+		}
+
+		for _, c := range cases {
+			if !c.check(c.err) {
+				t.Errorf("expected %s error: %d", c.name, response.CodeOf(c.err))
+			}
+		}
+
+		if response.IsTemporary(response.NewError("", 100)) || response.IsAuth(errors.New("other")) {
+			t.Errorf("unexpected category")
+		}
+	})
+
+	t.Run("generated codes match catalog", func(t *testing.T) {
+		data, err := os.ReadFile("error_codes.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var catalog []struct {
+			Code int    `json:"code"`
+			Name string `json:"name"`
+			En   string `json:"en"`
+			Ru   string `json:"ru"`
+		}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, entry := range catalog {
+			code := response.ErrorCode(entry.Code)
+			if code.String() != entry.Name || code.DescriptionEn() != entry.En || code.Description() != entry.Ru {
+				t.Errorf("error_codes.go is outdated for code %d, run go generate ./response", entry.Code)
+			}
+		}
+
+		if response.ErrorCode(123456).Known() {
+			t.Errorf("unexpected known code")
+		}
+	})
+}
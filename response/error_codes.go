@@ -0,0 +1,911 @@
+// Code generated by gen_error_codes.go from error_codes.json; DO NOT EDIT.
+
+package response
+
+// Коды ошибок VK API
+const (
+	// Произошла неизвестная ошибка
+	//
+	// Unknown error occurred
+	ErrorCodeUnknown ErrorCode = 1
+	// Приложение выключено
+	//
+	// Application is disabled
+	ErrorCodeAppDisabled ErrorCode = 2
+	// Передан неизвестный метод
+	//
+	// Unknown method passed
+	ErrorCodeUnknownMethod ErrorCode = 3
+	// Неверная подпись
+	//
+	// Incorrect signature
+	ErrorCodeInvalidSignature ErrorCode = 4
+	// Авторизация пользователя не удалась
+	//
+	// User authorization failed
+	ErrorCodeAuthFailed ErrorCode = 5
+	// Слишком много запросов в секунду
+	//
+	// Too many requests per second
+	ErrorCodeTooManyRequests ErrorCode = 6
+	// Нет прав для выполнения этого действия
+	//
+	// Permission to perform this action is denied
+	ErrorCodePermissionDenied ErrorCode = 7
+	// Неверный запрос
+	//
+	// Invalid request
+	ErrorCodeInvalidRequest ErrorCode = 8
+	// Слишком много однотипных действий
+	//
+	// Flood control
+	ErrorCodeFloodControl ErrorCode = 9
+	// Произошла внутренняя ошибка сервера
+	//
+	// Internal server error
+	ErrorCodeInternalServer ErrorCode = 10
+	// В тестовом режиме приложение должно быть выключено или пользователь должен быть залогинен
+	//
+	// In test mode application should be disabled or user should be authorized
+	ErrorCodeTestMode ErrorCode = 11
+	// Невозможно скомпилировать код
+	//
+	// Unable to compile code
+	ErrorCodeCompileCode ErrorCode = 12
+	// Ошибка выполнения кода
+	//
+	// Runtime error occurred during code invocation
+	ErrorCodeRuntimeCode ErrorCode = 13
+	// Требуется ввод кода с картинки (Captcha)
+	//
+	// Captcha needed
+	ErrorCodeCaptchaNeeded ErrorCode = 14
+	// Доступ запрещен
+	//
+	// Access denied
+	ErrorCodeAccessDenied ErrorCode = 15
+	// Требуется выполнение запросов по протоколу HTTPS
+	//
+	// HTTP authorization failed
+	ErrorCodeHttpsRequired ErrorCode = 16
+	// Требуется валидация пользователя
+	//
+	// Validation required
+	ErrorCodeValidationRequired ErrorCode = 17
+	// Страница удалена или заблокирована
+	//
+	// User was deleted or banned
+	ErrorCodeUserDeleted ErrorCode = 18
+	// Контент недоступен
+	//
+	// Content blocked
+	ErrorCodeContentBlocked ErrorCode = 19
+	// Данное действие запрещено для не Standalone приложений
+	//
+	// Permission to perform this action is denied for non-standalone applications
+	ErrorCodeNonStandaloneDenied ErrorCode = 20
+	// Данное действие разрешено только для Standalone и Open API приложений
+	//
+	// Permission to perform this action is allowed only for standalone and OpenAPI applications
+	ErrorCodeStandaloneOnly ErrorCode = 21
+	// Ошибка загрузки
+	//
+	// Upload error
+	ErrorCodeUploadError ErrorCode = 22
+	// Метод был выключен
+	//
+	// This method was disabled
+	ErrorCodeMethodDisabled ErrorCode = 23
+	// Требуется подтверждение со стороны пользователя
+	//
+	// Confirmation required
+	ErrorCodeConfirmationRequired ErrorCode = 24
+	// Требуется подтверждение токена
+	//
+	// Token confirmation required
+	ErrorCodeTokenConfirmationRequired ErrorCode = 25
+	// Ключ доступа сообщества недействителен
+	//
+	// Group authorization failed
+	ErrorCodeGroupAuthFailed ErrorCode = 27
+	// Ключ доступа приложения недействителен
+	//
+	// Application authorization failed
+	ErrorCodeAppAuthFailed ErrorCode = 28
+	// Достигнут количественный лимит на вызов метода
+	//
+	// Rate limit reached
+	ErrorCodeRateLimit ErrorCode = 29
+	// Профиль является приватным
+	//
+	// This profile is private
+	ErrorCodePrivateProfile ErrorCode = 30
+	// Метод еще не реализован
+	//
+	// Not implemented yet
+	ErrorCodeNotImplemented ErrorCode = 33
+	// Версия клиента устарела
+	//
+	// Client version deprecated
+	ErrorCodeClientVersionDeprecated ErrorCode = 34
+	// Требуется обновить клиент
+	//
+	// Client update needed
+	ErrorCodeClientUpdateNeeded ErrorCode = 35
+	// Пользователь заблокирован
+	//
+	// User was banned
+	ErrorCodeUserBanned ErrorCode = 37
+	// Неизвестное приложение
+	//
+	// Unknown application
+	ErrorCodeUnknownApplication ErrorCode = 38
+	// Неизвестный пользователь
+	//
+	// Unknown user
+	ErrorCodeUnknownUser ErrorCode = 39
+	// Неизвестное сообщество
+	//
+	// Unknown group
+	ErrorCodeUnknownGroup ErrorCode = 40
+	// Один из необходимых параметров был не передан или неверен
+	//
+	// One of the parameters specified was missing or invalid
+	ErrorCodeInvalidParam ErrorCode = 100
+	// Неверный API ID приложения
+	//
+	// Invalid application API ID
+	ErrorCodeInvalidAppId ErrorCode = 101
+	// Превышено ограничение
+	//
+	// Out of limits
+	ErrorCodeOutOfLimits ErrorCode = 103
+	// Не найдено
+	//
+	// Not found
+	ErrorCodeNotFound ErrorCode = 104
+	// Не удалось сохранить файл
+	//
+	// Couldn't save file
+	ErrorCodeSaveFile ErrorCode = 105
+	// Не удалось выполнить действие
+	//
+	// Unable to process action
+	ErrorCodeActionFailed ErrorCode = 106
+	// Неверный идентификатор пользователя
+	//
+	// Invalid user id
+	ErrorCodeInvalidUserId ErrorCode = 113
+	// Неверный идентификатор альбома
+	//
+	// Invalid album id
+	ErrorCodeInvalidAlbumId ErrorCode = 114
+	// Неверный сервер
+	//
+	// Invalid server
+	ErrorCodeInvalidServer ErrorCode = 118
+	// Недопустимое название
+	//
+	// Invalid title
+	ErrorCodeInvalidTitle ErrorCode = 119
+	// Неверный хеш
+	//
+	// Invalid hash
+	ErrorCodeInvalidHash ErrorCode = 121
+	// Неверный идентификатор фотографий
+	//
+	// Invalid photos
+	ErrorCodeInvalidPhotos ErrorCode = 122
+	// Неверный идентификатор сообщества
+	//
+	// Invalid group id
+	ErrorCodeInvalidGroupId ErrorCode = 125
+	// Недопустимый формат фотографии
+	//
+	// Invalid photo
+	ErrorCodeInvalidPhoto ErrorCode = 129
+	// Страница не найдена
+	//
+	// Page not found
+	ErrorCodePageNotFound ErrorCode = 140
+	// Нет доступа к странице
+	//
+	// Access to page denied
+	ErrorCodePageAccessDenied ErrorCode = 141
+	// Номер телефона пользователя неизвестен
+	//
+	// The mobile number of the user is unknown
+	ErrorCodeMobileNotActivated ErrorCode = 146
+	// Недостаточно средств на счете приложения
+	//
+	// Application has insufficient funds
+	ErrorCodeInsufficientFunds ErrorCode = 147
+	// Пользователь не установил приложение в левое меню
+	//
+	// Access to the menu of the user denied
+	ErrorCodeMenuAccessDenied ErrorCode = 148
+	// Неверный timestamp
+	//
+	// Invalid timestamp
+	ErrorCodeInvalidTimestamp ErrorCode = 150
+	// Неверный идентификатор списка
+	//
+	// Invalid list id
+	ErrorCodeInvalidFriendsListId ErrorCode = 171
+	// Достигнуто максимальное количество списков
+	//
+	// Reached the maximum number of lists
+	ErrorCodeFriendsListLimit ErrorCode = 173
+	// Невозможно добавить в друзья самого себя
+	//
+	// Cannot add user himself as friend
+	ErrorCodeFriendsAddYourself ErrorCode = 174
+	// Невозможно добавить в друзья пользователя, который добавил вас в черный список
+	//
+	// Cannot add this user to friends as they have put you on their blacklist
+	ErrorCodeFriendsAddInEnemy ErrorCode = 175
+	// Невозможно добавить в друзья пользователя, которого вы добавили в черный список
+	//
+	// Cannot add this user to friends as you put him on blacklist
+	ErrorCodeFriendsAddEnemy ErrorCode = 176
+	// Невозможно добавить в друзья пользователя, который не найден
+	//
+	// Cannot add this user to friends as user not found
+	ErrorCodeFriendsAddNotFound ErrorCode = 177
+	// Заметка не найдена
+	//
+	// Note not found
+	ErrorCodeNoteNotFound ErrorCode = 180
+	// Нет доступа к заметке
+	//
+	// Access to note denied
+	ErrorCodeNoteAccessDenied ErrorCode = 181
+	// Нельзя комментировать заметку
+	//
+	// You can't comment this note
+	ErrorCodeNoteCommentDenied ErrorCode = 182
+	// Нет доступа к комментарию
+	//
+	// Access to comment denied
+	ErrorCodeCommentAccessDenied ErrorCode = 183
+	// Доступ к альбому запрещен
+	//
+	// Access to album denied
+	ErrorCodeAlbumAccessDenied ErrorCode = 200
+	// Доступ к аудио запрещен
+	//
+	// Access to audio denied
+	ErrorCodeAudioAccessDenied ErrorCode = 201
+	// Доступ к группе запрещен
+	//
+	// Access to group denied
+	ErrorCodeGroupAccessDenied ErrorCode = 203
+	// Нет доступа к видео
+	//
+	// Access to video denied
+	ErrorCodeVideoAccessDenied ErrorCode = 204
+	// Нет доступа к товарам
+	//
+	// Access to market denied
+	ErrorCodeMarketAccessDenied ErrorCode = 205
+	// Доступ к записи на стене запрещен
+	//
+	// Access to wall's post denied
+	ErrorCodeWallPostAccessDenied ErrorCode = 210
+	// Доступ к комментарию на стене запрещен
+	//
+	// Access to wall's comment denied
+	ErrorCodeWallCommentAccessDenied ErrorCode = 211
+	// Доступ к комментариям записи запрещен
+	//
+	// Access to post comments denied
+	ErrorCodePostCommentsAccessDenied ErrorCode = 212
+	// Нет доступа к комментариям записи
+	//
+	// Access to status replies denied
+	ErrorCodeStatusRepliesAccessDenied ErrorCode = 213
+	// Публикация записей запрещена
+	//
+	// Access to adding post denied
+	ErrorCodeWallAddPostDenied ErrorCode = 214
+	// Рекламная запись недавно публиковалась
+	//
+	// Advertisement post was recently added
+	ErrorCodeAdsPostRecentlyAdded ErrorCode = 219
+	// Слишком много получателей
+	//
+	// Too many recipients
+	ErrorCodeTooManyRecipients ErrorCode = 220
+	// Пользователь выключил трансляцию названий аудиозаписей в статус
+	//
+	// User disabled track name broadcast
+	ErrorCodeStatusNoAudio ErrorCode = 221
+	// Запрещено размещать ссылки
+	//
+	// Hyperlinks are forbidden
+	ErrorCodeHyperlinksForbidden ErrorCode = 222
+	// Превышен лимит комментариев на стене
+	//
+	// Too many replies
+	ErrorCodeTooManyReplies ErrorCode = 223
+	// Превышен лимит рекламных записей
+	//
+	// Too many ads posts
+	ErrorCodeAdsPostLimitReached ErrorCode = 224
+	// Нет доступа к опросу
+	//
+	// Access to poll denied
+	ErrorCodePollAccessDenied ErrorCode = 250
+	// Неверный идентификатор опроса
+	//
+	// Invalid poll id
+	ErrorCodeInvalidPollId ErrorCode = 251
+	// Неверный идентификатор ответа
+	//
+	// Invalid answer id
+	ErrorCodeInvalidPollAnswerId ErrorCode = 252
+	// Доступ запрещен, сначала проголосуйте
+	//
+	// Access denied, please vote first
+	ErrorCodePollVoteRequired ErrorCode = 253
+	// Доступ к списку сообществ запрещен настройками приватности пользователя
+	//
+	// Access to the groups list is denied due to the user's privacy settings
+	ErrorCodeGroupsListAccessDenied ErrorCode = 260
+	// Альбом переполнен
+	//
+	// This album is full
+	ErrorCodeAlbumFull ErrorCode = 300
+	// Достигнуто максимальное количество альбомов
+	//
+	// Albums number limit is reached
+	ErrorCodeAlbumsLimit ErrorCode = 302
+	// Действие запрещено. Вы должны включить переводы голосов в настройках приложения
+	//
+	// Permission denied. You must enable votes processing in application settings
+	ErrorCodeVotesDisabled ErrorCode = 500
+	// Недостаточно голосов
+	//
+	// Not enough votes
+	ErrorCodeNotEnoughVotes ErrorCode = 503
+	// Нет прав на выполнение данных операций с рекламным кабинетом
+	//
+	// Permission denied. You have no access to operations specified with given object(s)
+	ErrorCodeAdsPermissionDenied ErrorCode = 600
+	// Превышено количество запросов за день. Попробуйте позже
+	//
+	// Permission denied. You have requested too many actions this day. Try later.
+	ErrorCodeAdsTooManyActions ErrorCode = 601
+	// Произошла ошибка при работе с рекламным кабинетом
+	//
+	// Some ads error occurred
+	ErrorCodeAdsError ErrorCode = 603
+	// Нельзя изменить права создателя
+	//
+	// Cannot edit creator role
+	ErrorCodeGroupChangeCreator ErrorCode = 700
+	// Пользователь должен состоять в сообществе
+	//
+	// User should be in club
+	ErrorCodeGroupNotInClub ErrorCode = 701
+	// Превышен лимит руководителей в сообществе
+	//
+	// Too many officers in club
+	ErrorCodeGroupTooManyOfficers ErrorCode = 702
+	// Для выполнения действия нужно включить двухфакторную аутентификацию
+	//
+	// You need to enable 2FA for this action
+	ErrorCodeGroupNeed2fa ErrorCode = 703
+	// Пользователю нужно включить двухфакторную аутентификацию для выполнения действия
+	//
+	// User needs to enable 2FA for this action
+	ErrorCodeGroupHostNeed2fa ErrorCode = 704
+	// Превышен лимит адресов в сообществе
+	//
+	// Too many addresses in club
+	ErrorCodeGroupTooManyAddresses ErrorCode = 706
+	// Приложение не установлено в сообществе
+	//
+	// Application is not installed in community
+	ErrorCodeAppNotInstalledInGroup ErrorCode = 711
+	// Видео уже добавлено
+	//
+	// This video is already added
+	ErrorCodeVideoAlreadyAdded ErrorCode = 800
+	// Комментарии к видео закрыты
+	//
+	// Comments for this video are closed
+	ErrorCodeVideoCommentsClosed ErrorCode = 801
+	// Нельзя отправлять сообщение пользователю из черного списка
+	//
+	// Can't send messages for users from blacklist
+	ErrorCodeMessagesBlacklisted ErrorCode = 900
+	// Пользователь запретил отправку сообщений от имени сообщества
+	//
+	// Can't send messages for users without permission
+	ErrorCodeMessagesDenySend ErrorCode = 901
+	// Нельзя отправлять сообщения этому пользователю в связи с настройками приватности
+	//
+	// Can't send messages to this user due to their privacy settings
+	ErrorCodeMessagesPrivacy ErrorCode = 902
+	// Нельзя редактировать сообщение, потому что оно слишком старое
+	//
+	// Can't edit this message, because it's too old
+	ErrorCodeMessageEditExpired ErrorCode = 909
+	// Нельзя отправить сообщение, потому что оно слишком большое
+	//
+	// Can't sent this message, because it's too big
+	ErrorCodeMessageTooBig ErrorCode = 910
+	// Неверный формат клавиатуры
+	//
+	// Keyboard format is invalid
+	ErrorCodeKeyboardInvalid ErrorCode = 911
+	// Это функция чат-бота, включите ее в настройках
+	//
+	// This is a chat bot feature, change this status in settings
+	ErrorCodeChatBotFeature ErrorCode = 912
+	// Слишком много пересланных сообщений
+	//
+	// Too many forwarded messages
+	ErrorCodeTooManyForwards ErrorCode = 913
+	// Сообщение слишком длинное
+	//
+	// Message is too long
+	ErrorCodeMessageTooLong ErrorCode = 914
+	// Нет доступа к беседе
+	//
+	// You don't have access to this chat
+	ErrorCodeChatAccessDenied ErrorCode = 917
+	// Невозможно переслать сообщения
+	//
+	// Can't forward these messages
+	ErrorCodeCantForwardMessages ErrorCode = 921
+	// Вы не являетесь администратором беседы
+	//
+	// You are not admin of this chat
+	ErrorCodeChatNotAdmin ErrorCode = 925
+	// Беседа не существует
+	//
+	// Chat does not exist
+	ErrorCodeChatNotExist ErrorCode = 927
+	// Контакт не найден
+	//
+	// Contact not found
+	ErrorCodeContactNotFound ErrorCode = 936
+	// Беседа отключена
+	//
+	// Chat was disabled
+	ErrorCodeChatDisabled ErrorCode = 945
+	// Беседа не поддерживается
+	//
+	// Chat not supported
+	ErrorCodeChatUnsupported ErrorCode = 946
+	// Требуется пройти Recaptcha
+	//
+	// Recaptcha needed
+	ErrorCodeRecaptchaNeeded ErrorCode = 3300
+	// Требуется подтверждение номера телефона
+	//
+	// Phone validation needed
+	ErrorCodePhoneValidationNeeded ErrorCode = 3301
+	// Требуется подтверждение пароля
+	//
+	// Password validation needed
+	ErrorCodePasswordValidationNeeded ErrorCode = 3302
+	// Требуется подтверждение кодом из приложения
+	//
+	// Otp app validation needed
+	ErrorCodeOtpValidationNeeded ErrorCode = 3303
+	// Требуется подтверждение адреса электронной почты
+	//
+	// Email confirmation needed
+	ErrorCodeEmailConfirmationNeeded ErrorCode = 3304
+	// Требуется подтверждение голосов
+	//
+	// Assert votes
+	ErrorCodeAssertVotes ErrorCode = 3305
+	// Требуется продление токена
+	//
+	// Token extension required
+	ErrorCodeTokenExtensionRequired ErrorCode = 3609
+	// Пользователь деактивирован
+	//
+	// User is deactivated
+	ErrorCodeUserDeactivated ErrorCode = 3610
+	// Сервис недоступен для пользователя
+	//
+	// Service is deactivated for user
+	ErrorCodeServiceDeactivated ErrorCode = 3611
+)
+
+// Ошибки VK API для сравнения через errors.Is(): ошибки совпадают, если совпадают их коды
+var (
+	// Произошла неизвестная ошибка
+	ErrUnknown = &Error{message: "Unknown error occurred", intCode: 1}
+	// Приложение выключено
+	ErrAppDisabled = &Error{message: "Application is disabled", intCode: 2}
+	// Передан неизвестный метод
+	ErrUnknownMethod = &Error{message: "Unknown method passed", intCode: 3}
+	// Неверная подпись
+	ErrInvalidSignature = &Error{message: "Incorrect signature", intCode: 4}
+	// Авторизация пользователя не удалась
+	ErrAuthFailed = &Error{message: "User authorization failed", intCode: 5}
+	// Слишком много запросов в секунду
+	ErrTooManyRequests = &Error{message: "Too many requests per second", intCode: 6}
+	// Нет прав для выполнения этого действия
+	ErrPermissionDenied = &Error{message: "Permission to perform this action is denied", intCode: 7}
+	// Неверный запрос
+	ErrInvalidRequest = &Error{message: "Invalid request", intCode: 8}
+	// Слишком много однотипных действий
+	ErrFloodControl = &Error{message: "Flood control", intCode: 9}
+	// Произошла внутренняя ошибка сервера
+	ErrInternalServer = &Error{message: "Internal server error", intCode: 10}
+	// В тестовом режиме приложение должно быть выключено или пользователь должен быть залогинен
+	ErrTestMode = &Error{message: "In test mode application should be disabled or user should be authorized", intCode: 11}
+	// Невозможно скомпилировать код
+	ErrCompileCode = &Error{message: "Unable to compile code", intCode: 12}
+	// Ошибка выполнения кода
+	ErrRuntimeCode = &Error{message: "Runtime error occurred during code invocation", intCode: 13}
+	// Требуется ввод кода с картинки (Captcha)
+	ErrCaptchaNeeded = &Error{message: "Captcha needed", intCode: 14}
+	// Доступ запрещен
+	ErrAccessDenied = &Error{message: "Access denied", intCode: 15}
+	// Требуется выполнение запросов по протоколу HTTPS
+	ErrHttpsRequired = &Error{message: "HTTP authorization failed", intCode: 16}
+	// Требуется валидация пользователя
+	ErrValidationRequired = &Error{message: "Validation required", intCode: 17}
+	// Страница удалена или заблокирована
+	ErrUserDeleted = &Error{message: "User was deleted or banned", intCode: 18}
+	// Контент недоступен
+	ErrContentBlocked = &Error{message: "Content blocked", intCode: 19}
+	// Данное действие запрещено для не Standalone приложений
+	ErrNonStandaloneDenied = &Error{message: "Permission to perform this action is denied for non-standalone applications", intCode: 20}
+	// Данное действие разрешено только для Standalone и Open API приложений
+	ErrStandaloneOnly = &Error{message: "Permission to perform this action is allowed only for standalone and OpenAPI applications", intCode: 21}
+	// Ошибка загрузки
+	ErrUploadError = &Error{message: "Upload error", intCode: 22}
+	// Метод был выключен
+	ErrMethodDisabled = &Error{message: "This method was disabled", intCode: 23}
+	// Требуется подтверждение со стороны пользователя
+	ErrConfirmationRequired = &Error{message: "Confirmation required", intCode: 24}
+	// Требуется подтверждение токена
+	ErrTokenConfirmationRequired = &Error{message: "Token confirmation required", intCode: 25}
+	// Ключ доступа сообщества недействителен
+	ErrGroupAuthFailed = &Error{message: "Group authorization failed", intCode: 27}
+	// Ключ доступа приложения недействителен
+	ErrAppAuthFailed = &Error{message: "Application authorization failed", intCode: 28}
+	// Достигнут количественный лимит на вызов метода
+	ErrRateLimit = &Error{message: "Rate limit reached", intCode: 29}
+	// Профиль является приватным
+	ErrPrivateProfile = &Error{message: "This profile is private", intCode: 30}
+	// Метод еще не реализован
+	ErrNotImplemented = &Error{message: "Not implemented yet", intCode: 33}
+	// Версия клиента устарела
+	ErrClientVersionDeprecated = &Error{message: "Client version deprecated", intCode: 34}
+	// Требуется обновить клиент
+	ErrClientUpdateNeeded = &Error{message: "Client update needed", intCode: 35}
+	// Пользователь заблокирован
+	ErrUserBanned = &Error{message: "User was banned", intCode: 37}
+	// Неизвестное приложение
+	ErrUnknownApplication = &Error{message: "Unknown application", intCode: 38}
+	// Неизвестный пользователь
+	ErrUnknownUser = &Error{message: "Unknown user", intCode: 39}
+	// Неизвестное сообщество
+	ErrUnknownGroup = &Error{message: "Unknown group", intCode: 40}
+	// Один из необходимых параметров был не передан или неверен
+	ErrInvalidParam = &Error{message: "One of the parameters specified was missing or invalid", intCode: 100}
+	// Неверный API ID приложения
+	ErrInvalidAppId = &Error{message: "Invalid application API ID", intCode: 101}
+	// Превышено ограничение
+	ErrOutOfLimits = &Error{message: "Out of limits", intCode: 103}
+	// Не найдено
+	ErrNotFound = &Error{message: "Not found", intCode: 104}
+	// Не удалось сохранить файл
+	ErrSaveFile = &Error{message: "Couldn't save file", intCode: 105}
+	// Не удалось выполнить действие
+	ErrActionFailed = &Error{message: "Unable to process action", intCode: 106}
+	// Неверный идентификатор пользователя
+	ErrInvalidUserId = &Error{message: "Invalid user id", intCode: 113}
+	// Неверный идентификатор альбома
+	ErrInvalidAlbumId = &Error{message: "Invalid album id", intCode: 114}
+	// Неверный сервер
+	ErrInvalidServer = &Error{message: "Invalid server", intCode: 118}
+	// Недопустимое название
+	ErrInvalidTitle = &Error{message: "Invalid title", intCode: 119}
+	// Неверный хеш
+	ErrInvalidHash = &Error{message: "Invalid hash", intCode: 121}
+	// Неверный идентификатор фотографий
+	ErrInvalidPhotos = &Error{message: "Invalid photos", intCode: 122}
+	// Неверный идентификатор сообщества
+	ErrInvalidGroupId = &Error{message: "Invalid group id", intCode: 125}
+	// Недопустимый формат фотографии
+	ErrInvalidPhoto = &Error{message: "Invalid photo", intCode: 129}
+	// Страница не найдена
+	ErrPageNotFound = &Error{message: "Page not found", intCode: 140}
+	// Нет доступа к странице
+	ErrPageAccessDenied = &Error{message: "Access to page denied", intCode: 141}
+	// Номер телефона пользователя неизвестен
+	ErrMobileNotActivated = &Error{message: "The mobile number of the user is unknown", intCode: 146}
+	// Недостаточно средств на счете приложения
+	ErrInsufficientFunds = &Error{message: "Application has insufficient funds", intCode: 147}
+	// Пользователь не установил приложение в левое меню
+	ErrMenuAccessDenied = &Error{message: "Access to the menu of the user denied", intCode: 148}
+	// Неверный timestamp
+	ErrInvalidTimestamp = &Error{message: "Invalid timestamp", intCode: 150}
+	// Неверный идентификатор списка
+	ErrInvalidFriendsListId = &Error{message: "Invalid list id", intCode: 171}
+	// Достигнуто максимальное количество списков
+	ErrFriendsListLimit = &Error{message: "Reached the maximum number of lists", intCode: 173}
+	// Невозможно добавить в друзья самого себя
+	ErrFriendsAddYourself = &Error{message: "Cannot add user himself as friend", intCode: 174}
+	// Невозможно добавить в друзья пользователя, который добавил вас в черный список
+	ErrFriendsAddInEnemy = &Error{message: "Cannot add this user to friends as they have put you on their blacklist", intCode: 175}
+	// Невозможно добавить в друзья пользователя, которого вы добавили в черный список
+	ErrFriendsAddEnemy = &Error{message: "Cannot add this user to friends as you put him on blacklist", intCode: 176}
+	// Невозможно добавить в друзья пользователя, который не найден
+	ErrFriendsAddNotFound = &Error{message: "Cannot add this user to friends as user not found", intCode: 177}
+	// Заметка не найдена
+	ErrNoteNotFound = &Error{message: "Note not found", intCode: 180}
+	// Нет доступа к заметке
+	ErrNoteAccessDenied = &Error{message: "Access to note denied", intCode: 181}
+	// Нельзя комментировать заметку
+	ErrNoteCommentDenied = &Error{message: "You can't comment this note", intCode: 182}
+	// Нет доступа к комментарию
+	ErrCommentAccessDenied = &Error{message: "Access to comment denied", intCode: 183}
+	// Доступ к альбому запрещен
+	ErrAlbumAccessDenied = &Error{message: "Access to album denied", intCode: 200}
+	// Доступ к аудио запрещен
+	ErrAudioAccessDenied = &Error{message: "Access to audio denied", intCode: 201}
+	// Доступ к группе запрещен
+	ErrGroupAccessDenied = &Error{message: "Access to group denied", intCode: 203}
+	// Нет доступа к видео
+	ErrVideoAccessDenied = &Error{message: "Access to video denied", intCode: 204}
+	// Нет доступа к товарам
+	ErrMarketAccessDenied = &Error{message: "Access to market denied", intCode: 205}
+	// Доступ к записи на стене запрещен
+	ErrWallPostAccessDenied = &Error{message: "Access to wall's post denied", intCode: 210}
+	// Доступ к комментарию на стене запрещен
+	ErrWallCommentAccessDenied = &Error{message: "Access to wall's comment denied", intCode: 211}
+	// Доступ к комментариям записи запрещен
+	ErrPostCommentsAccessDenied = &Error{message: "Access to post comments denied", intCode: 212}
+	// Нет доступа к комментариям записи
+	ErrStatusRepliesAccessDenied = &Error{message: "Access to status replies denied", intCode: 213}
+	// Публикация записей запрещена
+	ErrWallAddPostDenied = &Error{message: "Access to adding post denied", intCode: 214}
+	// Рекламная запись недавно публиковалась
+	ErrAdsPostRecentlyAdded = &Error{message: "Advertisement post was recently added", intCode: 219}
+	// Слишком много получателей
+	ErrTooManyRecipients = &Error{message: "Too many recipients", intCode: 220}
+	// Пользователь выключил трансляцию названий аудиозаписей в статус
+	ErrStatusNoAudio = &Error{message: "User disabled track name broadcast", intCode: 221}
+	// Запрещено размещать ссылки
+	ErrHyperlinksForbidden = &Error{message: "Hyperlinks are forbidden", intCode: 222}
+	// Превышен лимит комментариев на стене
+	ErrTooManyReplies = &Error{message: "Too many replies", intCode: 223}
+	// Превышен лимит рекламных записей
+	ErrAdsPostLimitReached = &Error{message: "Too many ads posts", intCode: 224}
+	// Нет доступа к опросу
+	ErrPollAccessDenied = &Error{message: "Access to poll denied", intCode: 250}
+	// Неверный идентификатор опроса
+	ErrInvalidPollId = &Error{message: "Invalid poll id", intCode: 251}
+	// Неверный идентификатор ответа
+	ErrInvalidPollAnswerId = &Error{message: "Invalid answer id", intCode: 252}
+	// Доступ запрещен, сначала проголосуйте
+	ErrPollVoteRequired = &Error{message: "Access denied, please vote first", intCode: 253}
+	// Доступ к списку сообществ запрещен настройками приватности пользователя
+	ErrGroupsListAccessDenied = &Error{message: "Access to the groups list is denied due to the user's privacy settings", intCode: 260}
+	// Альбом переполнен
+	ErrAlbumFull = &Error{message: "This album is full", intCode: 300}
+	// Достигнуто максимальное количество альбомов
+	ErrAlbumsLimit = &Error{message: "Albums number limit is reached", intCode: 302}
+	// Действие запрещено. Вы должны включить переводы голосов в настройках приложения
+	ErrVotesDisabled = &Error{message: "Permission denied. You must enable votes processing in application settings", intCode: 500}
+	// Недостаточно голосов
+	ErrNotEnoughVotes = &Error{message: "Not enough votes", intCode: 503}
+	// Нет прав на выполнение данных операций с рекламным кабинетом
+	ErrAdsPermissionDenied = &Error{message: "Permission denied. You have no access to operations specified with given object(s)", intCode: 600}
+	// Превышено количество запросов за день. Попробуйте позже
+	ErrAdsTooManyActions = &Error{message: "Permission denied. You have requested too many actions this day. Try later.", intCode: 601}
+	// Произошла ошибка при работе с рекламным кабинетом
+	ErrAdsError = &Error{message: "Some ads error occurred", intCode: 603}
+	// Нельзя изменить права создателя
+	ErrGroupChangeCreator = &Error{message: "Cannot edit creator role", intCode: 700}
+	// Пользователь должен состоять в сообществе
+	ErrGroupNotInClub = &Error{message: "User should be in club", intCode: 701}
+	// Превышен лимит руководителей в сообществе
+	ErrGroupTooManyOfficers = &Error{message: "Too many officers in club", intCode: 702}
+	// Для выполнения действия нужно включить двухфакторную аутентификацию
+	ErrGroupNeed2fa = &Error{message: "You need to enable 2FA for this action", intCode: 703}
+	// Пользователю нужно включить двухфакторную аутентификацию для выполнения действия
+	ErrGroupHostNeed2fa = &Error{message: "User needs to enable 2FA for this action", intCode: 704}
+	// Превышен лимит адресов в сообществе
+	ErrGroupTooManyAddresses = &Error{message: "Too many addresses in club", intCode: 706}
+	// Приложение не установлено в сообществе
+	ErrAppNotInstalledInGroup = &Error{message: "Application is not installed in community", intCode: 711}
+	// Видео уже добавлено
+	ErrVideoAlreadyAdded = &Error{message: "This video is already added", intCode: 800}
+	// Комментарии к видео закрыты
+	ErrVideoCommentsClosed = &Error{message: "Comments for this video are closed", intCode: 801}
+	// Нельзя отправлять сообщение пользователю из черного списка
+	ErrMessagesBlacklisted = &Error{message: "Can't send messages for users from blacklist", intCode: 900}
+	// Пользователь запретил отправку сообщений от имени сообщества
+	ErrMessagesDenySend = &Error{message: "Can't send messages for users without permission", intCode: 901}
+	// Нельзя отправлять сообщения этому пользователю в связи с настройками приватности
+	ErrMessagesPrivacy = &Error{message: "Can't send messages to this user due to their privacy settings", intCode: 902}
+	// Нельзя редактировать сообщение, потому что оно слишком старое
+	ErrMessageEditExpired = &Error{message: "Can't edit this message, because it's too old", intCode: 909}
+	// Нельзя отправить сообщение, потому что оно слишком большое
+	ErrMessageTooBig = &Error{message: "Can't sent this message, because it's too big", intCode: 910}
+	// Неверный формат клавиатуры
+	ErrKeyboardInvalid = &Error{message: "Keyboard format is invalid", intCode: 911}
+	// Это функция чат-бота, включите ее в настройках
+	ErrChatBotFeature = &Error{message: "This is a chat bot feature, change this status in settings", intCode: 912}
+	// Слишком много пересланных сообщений
+	ErrTooManyForwards = &Error{message: "Too many forwarded messages", intCode: 913}
+	// Сообщение слишком длинное
+	ErrMessageTooLong = &Error{message: "Message is too long", intCode: 914}
+	// Нет доступа к беседе
+	ErrChatAccessDenied = &Error{message: "You don't have access to this chat", intCode: 917}
+	// Невозможно переслать сообщения
+	ErrCantForwardMessages = &Error{message: "Can't forward these messages", intCode: 921}
+	// Вы не являетесь администратором беседы
+	ErrChatNotAdmin = &Error{message: "You are not admin of this chat", intCode: 925}
+	// Беседа не существует
+	ErrChatNotExist = &Error{message: "Chat does not exist", intCode: 927}
+	// Контакт не найден
+	ErrContactNotFound = &Error{message: "Contact not found", intCode: 936}
+	// Беседа отключена
+	ErrChatDisabled = &Error{message: "Chat was disabled", intCode: 945}
+	// Беседа не поддерживается
+	ErrChatUnsupported = &Error{message: "Chat not supported", intCode: 946}
+	// Требуется пройти Recaptcha
+	ErrRecaptchaNeeded = &Error{message: "Recaptcha needed", intCode: 3300}
+	// Требуется подтверждение номера телефона
+	ErrPhoneValidationNeeded = &Error{message: "Phone validation needed", intCode: 3301}
+	// Требуется подтверждение пароля
+	ErrPasswordValidationNeeded = &Error{message: "Password validation needed", intCode: 3302}
+	// Требуется подтверждение кодом из приложения
+	ErrOtpValidationNeeded = &Error{message: "Otp app validation needed", intCode: 3303}
+	// Требуется подтверждение адреса электронной почты
+	ErrEmailConfirmationNeeded = &Error{message: "Email confirmation needed", intCode: 3304}
+	// Требуется подтверждение голосов
+	ErrAssertVotes = &Error{message: "Assert votes", intCode: 3305}
+	// Требуется продление токена
+	ErrTokenExtensionRequired = &Error{message: "Token extension required", intCode: 3609}
+	// Пользователь деактивирован
+	ErrUserDeactivated = &Error{message: "User is deactivated", intCode: 3610}
+	// Сервис недоступен для пользователя
+	ErrServiceDeactivated = &Error{message: "Service is deactivated for user", intCode: 3611}
+)
+
+// Описания кодов ошибок
+var errorCodes = map[ErrorCode]errorCodeInfo{
+	ErrorCodeUnknown:                   {name: "Unknown", en: "Unknown error occurred", ru: "Произошла неизвестная ошибка", categories: categoryTemporary},
+	ErrorCodeAppDisabled:               {name: "AppDisabled", en: "Application is disabled", ru: "Приложение выключено", categories: 0},
+	ErrorCodeUnknownMethod:             {name: "UnknownMethod", en: "Unknown method passed", ru: "Передан неизвестный метод", categories: 0},
+	ErrorCodeInvalidSignature:          {name: "InvalidSignature", en: "Incorrect signature", ru: "Неверная подпись", categories: categoryAuth},
+	ErrorCodeAuthFailed:                {name: "AuthFailed", en: "User authorization failed", ru: "Авторизация пользователя не удалась", categories: categoryAuth},
+	ErrorCodeTooManyRequests:           {name: "TooManyRequests", en: "Too many requests per second", ru: "Слишком много запросов в секунду", categories: categoryRateLimit | categoryTemporary},
+	ErrorCodePermissionDenied:          {name: "PermissionDenied", en: "Permission to perform this action is denied", ru: "Нет прав для выполнения этого действия", categories: categoryPermission},
+	ErrorCodeInvalidRequest:            {name: "InvalidRequest", en: "Invalid request", ru: "Неверный запрос", categories: 0},
+	ErrorCodeFloodControl:              {name: "FloodControl", en: "Flood control", ru: "Слишком много однотипных действий", categories: categoryRateLimit | categoryTemporary},
+	ErrorCodeInternalServer:            {name: "InternalServer", en: "Internal server error", ru: "Произошла внутренняя ошибка сервера", categories: categoryTemporary},
+	ErrorCodeTestMode:                  {name: "TestMode", en: "In test mode application should be disabled or user should be authorized", ru: "В тестовом режиме приложение должно быть выключено или пользователь должен быть залогинен", categories: 0},
+	ErrorCodeCompileCode:               {name: "CompileCode", en: "Unable to compile code", ru: "Невозможно скомпилировать код", categories: 0},
+	ErrorCodeRuntimeCode:               {name: "RuntimeCode", en: "Runtime error occurred during code invocation", ru: "Ошибка выполнения кода", categories: 0},
+	ErrorCodeCaptchaNeeded:             {name: "CaptchaNeeded", en: "Captcha needed", ru: "Требуется ввод кода с картинки (Captcha)", categories: categoryCaptcha},
+	ErrorCodeAccessDenied:              {name: "AccessDenied", en: "Access denied", ru: "Доступ запрещен", categories: categoryPermission},
+	ErrorCodeHttpsRequired:             {name: "HttpsRequired", en: "HTTP authorization failed", ru: "Требуется выполнение запросов по протоколу HTTPS", categories: categoryAuth},
+	ErrorCodeValidationRequired:        {name: "ValidationRequired", en: "Validation required", ru: "Требуется валидация пользователя", categories: categoryValidation},
+	ErrorCodeUserDeleted:               {name: "UserDeleted", en: "User was deleted or banned", ru: "Страница удалена или заблокирована", categories: categoryPermission},
+	ErrorCodeContentBlocked:            {name: "ContentBlocked", en: "Content blocked", ru: "Контент недоступен", categories: categoryPermission},
+	ErrorCodeNonStandaloneDenied:       {name: "NonStandaloneDenied", en: "Permission to perform this action is denied for non-standalone applications", ru: "Данное действие запрещено для не Standalone приложений", categories: categoryPermission},
+	ErrorCodeStandaloneOnly:            {name: "StandaloneOnly", en: "Permission to perform this action is allowed only for standalone and OpenAPI applications", ru: "Данное действие разрешено только для Standalone и Open API приложений", categories: categoryPermission},
+	ErrorCodeUploadError:               {name: "UploadError", en: "Upload error", ru: "Ошибка загрузки", categories: 0},
+	ErrorCodeMethodDisabled:            {name: "MethodDisabled", en: "This method was disabled", ru: "Метод был выключен", categories: 0},
+	ErrorCodeConfirmationRequired:      {name: "ConfirmationRequired", en: "Confirmation required", ru: "Требуется подтверждение со стороны пользователя", categories: categoryValidation},
+	ErrorCodeTokenConfirmationRequired: {name: "TokenConfirmationRequired", en: "Token confirmation required", ru: "Требуется подтверждение токена", categories: categoryValidation},
+	ErrorCodeGroupAuthFailed:           {name: "GroupAuthFailed", en: "Group authorization failed", ru: "Ключ доступа сообщества недействителен", categories: categoryAuth},
+	ErrorCodeAppAuthFailed:             {name: "AppAuthFailed", en: "Application authorization failed", ru: "Ключ доступа приложения недействителен", categories: categoryAuth},
+	ErrorCodeRateLimit:                 {name: "RateLimit", en: "Rate limit reached", ru: "Достигнут количественный лимит на вызов метода", categories: categoryRateLimit},
+	ErrorCodePrivateProfile:            {name: "PrivateProfile", en: "This profile is private", ru: "Профиль является приватным", categories: categoryPermission},
+	ErrorCodeNotImplemented:            {name: "NotImplemented", en: "Not implemented yet", ru: "Метод еще не реализован", categories: 0},
+	ErrorCodeClientVersionDeprecated:   {name: "ClientVersionDeprecated", en: "Client version deprecated", ru: "Версия клиента устарела", categories: 0},
+	ErrorCodeClientUpdateNeeded:        {name: "ClientUpdateNeeded", en: "Client update needed", ru: "Требуется обновить клиент", categories: 0},
+	ErrorCodeUserBanned:                {name: "UserBanned", en: "User was banned", ru: "Пользователь заблокирован", categories: categoryPermission},
+	ErrorCodeUnknownApplication:        {name: "UnknownApplication", en: "Unknown application", ru: "Неизвестное приложение", categories: 0},
+	ErrorCodeUnknownUser:               {name: "UnknownUser", en: "Unknown user", ru: "Неизвестный пользователь", categories: 0},
+	ErrorCodeUnknownGroup:              {name: "UnknownGroup", en: "Unknown group", ru: "Неизвестное сообщество", categories: 0},
+	ErrorCodeInvalidParam:              {name: "InvalidParam", en: "One of the parameters specified was missing or invalid", ru: "Один из необходимых параметров был не передан или неверен", categories: 0},
+	ErrorCodeInvalidAppId:              {name: "InvalidAppId", en: "Invalid application API ID", ru: "Неверный API ID приложения", categories: 0},
+	ErrorCodeOutOfLimits:               {name: "OutOfLimits", en: "Out of limits", ru: "Превышено ограничение", categories: 0},
+	ErrorCodeNotFound:                  {name: "NotFound", en: "Not found", ru: "Не найдено", categories: 0},
+	ErrorCodeSaveFile:                  {name: "SaveFile", en: "Couldn't save file", ru: "Не удалось сохранить файл", categories: 0},
+	ErrorCodeActionFailed:              {name: "ActionFailed", en: "Unable to process action", ru: "Не удалось выполнить действие", categories: 0},
+	ErrorCodeInvalidUserId:             {name: "InvalidUserId", en: "Invalid user id", ru: "Неверный идентификатор пользователя", categories: 0},
+	ErrorCodeInvalidAlbumId:            {name: "InvalidAlbumId", en: "Invalid album id", ru: "Неверный идентификатор альбома", categories: 0},
+	ErrorCodeInvalidServer:             {name: "InvalidServer", en: "Invalid server", ru: "Неверный сервер", categories: 0},
+	ErrorCodeInvalidTitle:              {name: "InvalidTitle", en: "Invalid title", ru: "Недопустимое название", categories: 0},
+	ErrorCodeInvalidHash:               {name: "InvalidHash", en: "Invalid hash", ru: "Неверный хеш", categories: 0},
+	ErrorCodeInvalidPhotos:             {name: "InvalidPhotos", en: "Invalid photos", ru: "Неверный идентификатор фотографий", categories: 0},
+	ErrorCodeInvalidGroupId:            {name: "InvalidGroupId", en: "Invalid group id", ru: "Неверный идентификатор сообщества", categories: 0},
+	ErrorCodeInvalidPhoto:              {name: "InvalidPhoto", en: "Invalid photo", ru: "Недопустимый формат фотографии", categories: 0},
+	ErrorCodePageNotFound:              {name: "PageNotFound", en: "Page not found", ru: "Страница не найдена", categories: 0},
+	ErrorCodePageAccessDenied:          {name: "PageAccessDenied", en: "Access to page denied", ru: "Нет доступа к странице", categories: categoryPermission},
+	ErrorCodeMobileNotActivated:        {name: "MobileNotActivated", en: "The mobile number of the user is unknown", ru: "Номер телефона пользователя неизвестен", categories: 0},
+	ErrorCodeInsufficientFunds:         {name: "InsufficientFunds", en: "Application has insufficient funds", ru: "Недостаточно средств на счете приложения", categories: 0},
+	ErrorCodeMenuAccessDenied:          {name: "MenuAccessDenied", en: "Access to the menu of the user denied", ru: "Пользователь не установил приложение в левое меню", categories: categoryPermission},
+	ErrorCodeInvalidTimestamp:          {name: "InvalidTimestamp", en: "Invalid timestamp", ru: "Неверный timestamp", categories: 0},
+	ErrorCodeInvalidFriendsListId:      {name: "InvalidFriendsListId", en: "Invalid list id", ru: "Неверный идентификатор списка", categories: 0},
+	ErrorCodeFriendsListLimit:          {name: "FriendsListLimit", en: "Reached the maximum number of lists", ru: "Достигнуто максимальное количество списков", categories: 0},
+	ErrorCodeFriendsAddYourself:        {name: "FriendsAddYourself", en: "Cannot add user himself as friend", ru: "Невозможно добавить в друзья самого себя", categories: 0},
+	ErrorCodeFriendsAddInEnemy:         {name: "FriendsAddInEnemy", en: "Cannot add this user to friends as they have put you on their blacklist", ru: "Невозможно добавить в друзья пользователя, который добавил вас в черный список", categories: categoryPermission},
+	ErrorCodeFriendsAddEnemy:           {name: "FriendsAddEnemy", en: "Cannot add this user to friends as you put him on blacklist", ru: "Невозможно добавить в друзья пользователя, которого вы добавили в черный список", categories: categoryPermission},
+	ErrorCodeFriendsAddNotFound:        {name: "FriendsAddNotFound", en: "Cannot add this user to friends as user not found", ru: "Невозможно добавить в друзья пользователя, который не найден", categories: 0},
+	ErrorCodeNoteNotFound:              {name: "NoteNotFound", en: "Note not found", ru: "Заметка не найдена", categories: 0},
+	ErrorCodeNoteAccessDenied:          {name: "NoteAccessDenied", en: "Access to note denied", ru: "Нет доступа к заметке", categories: categoryPermission},
+	ErrorCodeNoteCommentDenied:         {name: "NoteCommentDenied", en: "You can't comment this note", ru: "Нельзя комментировать заметку", categories: categoryPermission},
+	ErrorCodeCommentAccessDenied:       {name: "CommentAccessDenied", en: "Access to comment denied", ru: "Нет доступа к комментарию", categories: categoryPermission},
+	ErrorCodeAlbumAccessDenied:         {name: "AlbumAccessDenied", en: "Access to album denied", ru: "Доступ к альбому запрещен", categories: categoryPermission},
+	ErrorCodeAudioAccessDenied:         {name: "AudioAccessDenied", en: "Access to audio denied", ru: "Доступ к аудио запрещен", categories: categoryPermission},
+	ErrorCodeGroupAccessDenied:         {name: "GroupAccessDenied", en: "Access to group denied", ru: "Доступ к группе запрещен", categories: categoryPermission},
+	ErrorCodeVideoAccessDenied:         {name: "VideoAccessDenied", en: "Access to video denied", ru: "Нет доступа к видео", categories: categoryPermission},
+	ErrorCodeMarketAccessDenied:        {name: "MarketAccessDenied", en: "Access to market denied", ru: "Нет доступа к товарам", categories: categoryPermission},
+	ErrorCodeWallPostAccessDenied:      {name: "WallPostAccessDenied", en: "Access to wall's post denied", ru: "Доступ к записи на стене запрещен", categories: categoryPermission},
+	ErrorCodeWallCommentAccessDenied:   {name: "WallCommentAccessDenied", en: "Access to wall's comment denied", ru: "Доступ к комментарию на стене запрещен", categories: categoryPermission},
+	ErrorCodePostCommentsAccessDenied:  {name: "PostCommentsAccessDenied", en: "Access to post comments denied", ru: "Доступ к комментариям записи запрещен", categories: categoryPermission},
+	ErrorCodeStatusRepliesAccessDenied: {name: "StatusRepliesAccessDenied", en: "Access to status replies denied", ru: "Нет доступа к комментариям записи", categories: categoryPermission},
+	ErrorCodeWallAddPostDenied:         {name: "WallAddPostDenied", en: "Access to adding post denied", ru: "Публикация записей запрещена", categories: categoryPermission},
+	ErrorCodeAdsPostRecentlyAdded:      {name: "AdsPostRecentlyAdded", en: "Advertisement post was recently added", ru: "Рекламная запись недавно публиковалась", categories: categoryRateLimit},
+	ErrorCodeTooManyRecipients:         {name: "TooManyRecipients", en: "Too many recipients", ru: "Слишком много получателей", categories: 0},
+	ErrorCodeStatusNoAudio:             {name: "StatusNoAudio", en: "User disabled track name broadcast", ru: "Пользователь выключил трансляцию названий аудиозаписей в статус", categories: 0},
+	ErrorCodeHyperlinksForbidden:       {name: "HyperlinksForbidden", en: "Hyperlinks are forbidden", ru: "Запрещено размещать ссылки", categories: 0},
+	ErrorCodeTooManyReplies:            {name: "TooManyReplies", en: "Too many replies", ru: "Превышен лимит комментариев на стене", categories: categoryRateLimit},
+	ErrorCodeAdsPostLimitReached:       {name: "AdsPostLimitReached", en: "Too many ads posts", ru: "Превышен лимит рекламных записей", categories: categoryRateLimit},
+	ErrorCodePollAccessDenied:          {name: "PollAccessDenied", en: "Access to poll denied", ru: "Нет доступа к опросу", categories: categoryPermission},
+	ErrorCodeInvalidPollId:             {name: "InvalidPollId", en: "Invalid poll id", ru: "Неверный идентификатор опроса", categories: 0},
+	ErrorCodeInvalidPollAnswerId:       {name: "InvalidPollAnswerId", en: "Invalid answer id", ru: "Неверный идентификатор ответа", categories: 0},
+	ErrorCodePollVoteRequired:          {name: "PollVoteRequired", en: "Access denied, please vote first", ru: "Доступ запрещен, сначала проголосуйте", categories: categoryPermission},
+	ErrorCodeGroupsListAccessDenied:    {name: "GroupsListAccessDenied", en: "Access to the groups list is denied due to the user's privacy settings", ru: "Доступ к списку сообществ запрещен настройками приватности пользователя", categories: categoryPermission},
+	ErrorCodeAlbumFull:                 {name: "AlbumFull", en: "This album is full", ru: "Альбом переполнен", categories: 0},
+	ErrorCodeAlbumsLimit:               {name: "AlbumsLimit", en: "Albums number limit is reached", ru: "Достигнуто максимальное количество альбомов", categories: 0},
+	ErrorCodeVotesDisabled:             {name: "VotesDisabled", en: "Permission denied. You must enable votes processing in application settings", ru: "Действие запрещено. Вы должны включить переводы голосов в настройках приложения", categories: categoryPermission},
+	ErrorCodeNotEnoughVotes:            {name: "NotEnoughVotes", en: "Not enough votes", ru: "Недостаточно голосов", categories: 0},
+	ErrorCodeAdsPermissionDenied:       {name: "AdsPermissionDenied", en: "Permission denied. You have no access to operations specified with given object(s)", ru: "Нет прав на выполнение данных операций с рекламным кабинетом", categories: categoryPermission},
+	ErrorCodeAdsTooManyActions:         {name: "AdsTooManyActions", en: "Permission denied. You have requested too many actions this day. Try later.", ru: "Превышено количество запросов за день. Попробуйте позже", categories: categoryRateLimit},
+	ErrorCodeAdsError:                  {name: "AdsError", en: "Some ads error occurred", ru: "Произошла ошибка при работе с рекламным кабинетом", categories: 0},
+	ErrorCodeGroupChangeCreator:        {name: "GroupChangeCreator", en: "Cannot edit creator role", ru: "Нельзя изменить права создателя", categories: categoryPermission},
+	ErrorCodeGroupNotInClub:            {name: "GroupNotInClub", en: "User should be in club", ru: "Пользователь должен состоять в сообществе", categories: 0},
+	ErrorCodeGroupTooManyOfficers:      {name: "GroupTooManyOfficers", en: "Too many officers in club", ru: "Превышен лимит руководителей в сообществе", categories: 0},
+	ErrorCodeGroupNeed2fa:              {name: "GroupNeed2fa", en: "You need to enable 2FA for this action", ru: "Для выполнения действия нужно включить двухфакторную аутентификацию", categories: categoryValidation},
+	ErrorCodeGroupHostNeed2fa:          {name: "GroupHostNeed2fa", en: "User needs to enable 2FA for this action", ru: "Пользователю нужно включить двухфакторную аутентификацию для выполнения действия", categories: categoryValidation},
+	ErrorCodeGroupTooManyAddresses:     {name: "GroupTooManyAddresses", en: "Too many addresses in club", ru: "Превышен лимит адресов в сообществе", categories: 0},
+	ErrorCodeAppNotInstalledInGroup:    {name: "AppNotInstalledInGroup", en: "Application is not installed in community", ru: "Приложение не установлено в сообществе", categories: 0},
+	ErrorCodeVideoAlreadyAdded:         {name: "VideoAlreadyAdded", en: "This video is already added", ru: "Видео уже добавлено", categories: 0},
+	ErrorCodeVideoCommentsClosed:       {name: "VideoCommentsClosed", en: "Comments for this video are closed", ru: "Комментарии к видео закрыты", categories: categoryPermission},
+	ErrorCodeMessagesBlacklisted:       {name: "MessagesBlacklisted", en: "Can't send messages for users from blacklist", ru: "Нельзя отправлять сообщение пользователю из черного списка", categories: categoryPermission},
+	ErrorCodeMessagesDenySend:          {name: "MessagesDenySend", en: "Can't send messages for users without permission", ru: "Пользователь запретил отправку сообщений от имени сообщества", categories: categoryPermission},
+	ErrorCodeMessagesPrivacy:           {name: "MessagesPrivacy", en: "Can't send messages to this user due to their privacy settings", ru: "Нельзя отправлять сообщения этому пользователю в связи с настройками приватности", categories: categoryPermission},
+	ErrorCodeMessageEditExpired:        {name: "MessageEditExpired", en: "Can't edit this message, because it's too old", ru: "Нельзя редактировать сообщение, потому что оно слишком старое", categories: 0},
+	ErrorCodeMessageTooBig:             {name: "MessageTooBig", en: "Can't sent this message, because it's too big", ru: "Нельзя отправить сообщение, потому что оно слишком большое", categories: 0},
+	ErrorCodeKeyboardInvalid:           {name: "KeyboardInvalid", en: "Keyboard format is invalid", ru: "Неверный формат клавиатуры", categories: 0},
+	ErrorCodeChatBotFeature:            {name: "ChatBotFeature", en: "This is a chat bot feature, change this status in settings", ru: "Это функция чат-бота, включите ее в настройках", categories: 0},
+	ErrorCodeTooManyForwards:           {name: "TooManyForwards", en: "Too many forwarded messages", ru: "Слишком много пересланных сообщений", categories: 0},
+	ErrorCodeMessageTooLong:            {name: "MessageTooLong", en: "Message is too long", ru: "Сообщение слишком длинное", categories: 0},
+	ErrorCodeChatAccessDenied:          {name: "ChatAccessDenied", en: "You don't have access to this chat", ru: "Нет доступа к беседе", categories: categoryPermission},
+	ErrorCodeCantForwardMessages:       {name: "CantForwardMessages", en: "Can't forward these messages", ru: "Невозможно переслать сообщения", categories: 0},
+	ErrorCodeChatNotAdmin:              {name: "ChatNotAdmin", en: "You are not admin of this chat", ru: "Вы не являетесь администратором беседы", categories: categoryPermission},
+	ErrorCodeChatNotExist:              {name: "ChatNotExist", en: "Chat does not exist", ru: "Беседа не существует", categories: 0},
+	ErrorCodeContactNotFound:           {name: "ContactNotFound", en: "Contact not found", ru: "Контакт не найден", categories: 0},
+	ErrorCodeChatDisabled:              {name: "ChatDisabled", en: "Chat was disabled", ru: "Беседа отключена", categories: 0},
+	ErrorCodeChatUnsupported:           {name: "ChatUnsupported", en: "Chat not supported", ru: "Беседа не поддерживается", categories: 0},
+	ErrorCodeRecaptchaNeeded:           {name: "RecaptchaNeeded", en: "Recaptcha needed", ru: "Требуется пройти Recaptcha", categories: categoryCaptcha},
+	ErrorCodePhoneValidationNeeded:     {name: "PhoneValidationNeeded", en: "Phone validation needed", ru: "Требуется подтверждение номера телефона", categories: categoryValidation},
+	ErrorCodePasswordValidationNeeded:  {name: "PasswordValidationNeeded", en: "Password validation needed", ru: "Требуется подтверждение пароля", categories: categoryValidation},
+	ErrorCodeOtpValidationNeeded:       {name: "OtpValidationNeeded", en: "Otp app validation needed", ru: "Требуется подтверждение кодом из приложения", categories: categoryValidation},
+	ErrorCodeEmailConfirmationNeeded:   {name: "EmailConfirmationNeeded", en: "Email confirmation needed", ru: "Требуется подтверждение адреса электронной почты", categories: categoryValidation},
+	ErrorCodeAssertVotes:               {name: "AssertVotes", en: "Assert votes", ru: "Требуется подтверждение голосов", categories: 0},
+	ErrorCodeTokenExtensionRequired:    {name: "TokenExtensionRequired", en: "Token extension required", ru: "Требуется продление токена", categories: categoryAuth},
+	ErrorCodeUserDeactivated:           {name: "UserDeactivated", en: "User is deactivated", ru: "Пользователь деактивирован", categories: categoryPermission},
+	ErrorCodeServiceDeactivated:        {name: "ServiceDeactivated", en: "Service is deactivated for user", ru: "Сервис недоступен для пользователя", categories: categoryPermission},
+}
@@ -1,6 +1,9 @@
 package response
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 type ExecuteErrors struct {
 	errors []*Error
@@ -19,3 +22,15 @@ func NewExecuteErrors(errors []*Error) *ExecuteErrors {
 		errors: errors,
 	}
 }
+
+// Сообщает, есть ли среди ошибок вызовов ошибка target, чтобы работал errors.Is(err, response.ErrAccessDenied).
+// Unwrap() не реализован намеренно: ошибка одного вызова не должна находиться через errors.As() как ошибка
+// всего запроса execute, иначе хуки повторов, пулы токенов и предохранители реагируют на нее как на ошибку запроса
+func (e *ExecuteErrors) Is(target error) bool {
+	for _, err := range e.errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
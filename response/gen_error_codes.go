@@ -0,0 +1,92 @@
+//go:build ignore
+
+// Генератор констант кодов ошибок VK API из error_codes.json.
+// Запускается через go generate ./response
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+// Описание кода ошибки в error_codes.json
+type errorCode struct {
+	Code       int      `json:"code"`
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+	En         string   `json:"en"`
+	Ru         string   `json:"ru"`
+}
+
+// Имена констант категорий в error_code.go
+var categories = map[string]string{
+	"auth":       "categoryAuth",
+	"permission": "categoryPermission",
+	"rate_limit": "categoryRateLimit",
+	"temporary":  "categoryTemporary",
+	"captcha":    "categoryCaptcha",
+	"validation": "categoryValidation",
+}
+
+func main() {
+	data, err := os.ReadFile("error_codes.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var codes []errorCode
+	if err := json.Unmarshal(data, &codes); err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_error_codes.go from error_codes.json; DO NOT EDIT.\n\n")
+	buf.WriteString("package response\n\n")
+
+	buf.WriteString("// Коды ошибок VK API\nconst (\n")
+	for _, code := range codes {
+		fmt.Fprintf(&buf, "\t// %s\n\t//\n\t// %s\n\tErrorCode%s ErrorCode = %d\n", code.Ru, code.En, code.Name, code.Code)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// Ошибки VK API для сравнения через errors.Is(): ошибки совпадают, если совпадают их коды\nvar (\n")
+	for _, code := range codes {
+		fmt.Fprintf(&buf, "\t// %s\n\tErr%s = &Error{message: %q, intCode: %d}\n", code.Ru, code.Name, code.En, code.Code)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// Описания кодов ошибок\nvar errorCodes = map[ErrorCode]errorCodeInfo{\n")
+	for _, code := range codes {
+		flags := []string{}
+		for _, category := range code.Categories {
+			flag, ok := categories[category]
+			if !ok {
+				log.Fatalf("unknown category %q of error code %d", category, code.Code)
+			}
+			flags = append(flags, flag)
+		}
+
+		categoryExpr := "0"
+		if len(flags) > 0 {
+			categoryExpr = strings.Join(flags, " | ")
+		}
+
+		fmt.Fprintf(&buf, "\tErrorCode%s: {name: %q, en: %q, ru: %q, categories: %s},\n",
+			code.Name, code.Name, code.En, code.Ru, categoryExpr)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile("error_codes.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,8 +1,11 @@
 package response_test
 
 import (
+	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/ciricc/vkapiexecutor/request"
@@ -63,3 +66,74 @@ func TestResponseWithoutContext(t *testing.T) {
 		}
 	})
 }
+
+func TestResponseBodyLimit(t *testing.T) {
+	newHttpResponse := func(body string) *http.Response {
+		return &http.Response{
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+		}
+	}
+
+	t.Run("body in limit", func(t *testing.T) {
+		res, err := response.NewUnknownLimit(newHttpResponse(`{"response":1}`), 14)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if res.String() != `{"response":1}` {
+			t.Errorf("unexpected body: %q", res.String())
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		_, err := response.NewUnknownLimit(newHttpResponse(`{"response":1}`), 13)
+		if !errors.Is(err, response.ErrBodyTooLarge) {
+			t.Errorf("expected body too large error, got: %v", err)
+		}
+	})
+
+	t.Run("body too large after empty reads", func(t *testing.T) {
+		body := io.MultiReader(strings.NewReader(`{"response":1}`), &emptyReader{n: 3}, strings.NewReader(`   `))
+
+		_, err := response.NewUnknownLimit(&http.Response{Body: io.NopCloser(body)}, 14)
+		if !errors.Is(err, response.ErrBodyTooLarge) {
+			t.Errorf("expected body too large error, got: %v", err)
+		}
+	})
+
+	t.Run("read error after limit", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		body := io.MultiReader(strings.NewReader(`{"response":1}`), &emptyReader{n: 1, err: readErr})
+
+		_, err := response.NewUnknownLimit(&http.Response{Body: io.NopCloser(body)}, 14)
+		if !errors.Is(err, readErr) {
+			t.Errorf("expected read error, got: %v", err)
+		}
+	})
+
+	t.Run("without limit", func(t *testing.T) {
+		res := response.NewUnknown(newHttpResponse(`{"response":1}`))
+		if res.String() != `{"response":1}` {
+			t.Errorf("unexpected body: %q", res.String())
+		}
+	})
+}
+
+// Reader, возвращающий n раз (0, nil), а затем err или io.EOF
+type emptyReader struct {
+	n   int
+	err error
+}
+
+func (v *emptyReader) Read(p []byte) (int, error) {
+	if v.n > 0 {
+		v.n--
+		return 0, nil
+	}
+
+	if v.err != nil {
+		return 0, v.err
+	}
+	return 0, io.EOF
+}
@@ -3,10 +3,14 @@ package response
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 )
 
+// Возвращается, если тело ответа больше допустимого размера
+var ErrBodyTooLarge = errors.New("response body too large")
+
 // Реализация неизвестного формата ответа
 // Используется для наследования от него и дальнейшего написания обработки под конкретный формат
 type UnknownResponse struct {
@@ -16,12 +20,29 @@ type UnknownResponse struct {
 }
 
 func NewUnknown(httpResponse *http.Response) *UnknownResponse {
-	bodyBytes := bytes.NewBuffer(make([]byte, 0))
-	io.Copy(bodyBytes, httpResponse.Body)
+	res, _ := NewUnknownLimit(httpResponse, 0)
+	return res
+}
+
+// Создает ответ, читая тело не больше maxBodySize байт.
+// Если тело больше, возвращает ответ с прочитанной частью тела и ошибку response.ErrBodyTooLarge.
+// Значение maxBodySize <= 0 снимает ограничение
+func NewUnknownLimit(httpResponse *http.Response, maxBodySize int64) (*UnknownResponse, error) {
+	bodyBytes := bytes.NewBuffer(make([]byte, 0, bodyCapacity(httpResponse.ContentLength, maxBodySize)))
+	_, err := io.Copy(bodyBytes, LimitBody(httpResponse.Body, maxBodySize))
 
 	return &UnknownResponse{
 		response:  httpResponse,
 		bodyBytes: bodyBytes.Bytes(),
+	}, err
+}
+
+// Создает ответ с уже прочитанным телом.
+// Тело HTTP ответа при этом не читается, например, при разделении ответа execute на части
+func NewUnknownWithBody(httpResponse *http.Response, body []byte) *UnknownResponse {
+	return &UnknownResponse{
+		response:  httpResponse,
+		bodyBytes: body,
 	}
 }
 
@@ -61,3 +82,71 @@ func (v *UnknownResponse) Renew(renew bool) {
 func (v *UnknownResponse) IsRenew() bool {
 	return v.renew
 }
+
+// Возвращает reader, который читает не больше maxBodySize байт и возвращает
+// response.ErrBodyTooLarge при попытке прочитать больше. Значение maxBodySize <= 0 снимает ограничение
+func LimitBody(body io.Reader, maxBodySize int64) io.Reader {
+	if maxBodySize <= 0 {
+		return body
+	}
+	return &limitedBody{body: body, left: maxBodySize}
+}
+
+// Reader с ограничением размера тела
+type limitedBody struct {
+	body io.Reader
+	left int64
+}
+
+func (v *limitedBody) Read(p []byte) (int, error) {
+	if v.left <= 0 {
+		return 0, v.probe()
+	}
+
+	if int64(len(p)) > v.left {
+		p = p[:v.left]
+	}
+
+	n, err := v.body.Read(p)
+	v.left -= int64(n)
+	return n, err
+}
+
+// Проверяет, что тело закончилось после прочитанного лимита.
+// Reader может вернуть (0, nil), поэтому чтение повторяется до данных или ошибки
+func (v *limitedBody) probe() error {
+	var probe [1]byte
+	for i := 0; i < maxEmptyReads; i++ {
+		n, err := v.body.Read(probe[:])
+		if n > 0 {
+			return ErrBodyTooLarge
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return io.ErrNoProgress
+}
+
+// Максимальное количество пустых чтений подряд, как в bufio
+const maxEmptyReads = 100
+
+// Возвращает начальный размер буфера для тела ответа
+func bodyCapacity(contentLength, maxBodySize int64) int64 {
+	const maxPrealloc = 1 << 20
+
+	if contentLength <= 0 {
+		return 0
+	}
+
+	if maxBodySize > 0 && contentLength > maxBodySize {
+		contentLength = maxBodySize
+	}
+
+	if contentLength > maxPrealloc {
+		return maxPrealloc
+	}
+
+	return contentLength
+}
@@ -0,0 +1,277 @@
+// Пакет tokenpool реализует пул токенов доступа с ротацией и карантином нерабочих токенов
+package tokenpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/limiter"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Возвращается, если в пуле нет доступных токенов
+var ErrNoTokens = errors.New("no available tokens in pool")
+
+// Время карантина токенов по кодам ошибок VK API по умолчанию:
+// 5 - авторизация не удалась, 15 - доступ запрещен, 30 - профиль приватный
+func DefaultQuarantine() map[int]time.Duration {
+	return map[int]time.Duration{
+		int(response.ErrorCodeAuthFailed):     24 * time.Hour,
+		int(response.ErrorCodeAccessDenied):   10 * time.Minute,
+		int(response.ErrorCodePrivateProfile): 10 * time.Minute,
+	}
+}
+
+// Ключ закрепления токена в контексте
+type stickyKeyContextKey struct{}
+
+// Закрепляет за запросами с одинаковым ключом один и тот же токен, пока он доступен
+//
+//	ctx = tokenpool.WithStickyKey(ctx, "user:1")
+func WithStickyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, stickyKeyContextKey{}, key)
+}
+
+// Пул токенов доступа. Реализует executor.TokenProvider.
+// Для каждого запроса выбирает давнее всех использованный доступный токен,
+// предпочитая токены, лимитер которых готов пропустить запрос без ожидания.
+// Токены, получившие ошибки из таблицы Quarantine, исключаются на время карантина,
+// а запрос переотправляется executor'ом с другим токеном
+//
+//	exec.Tokens = tokenpool.New(tokens...)
+type Pool struct {
+	// Время карантина по кодам ошибок VK API
+	Quarantine map[int]time.Duration
+	// Хранилище лимитеров, используемое limiter.Tripper в HTTP клиенте executor'а
+	Limiters limiter.LimiterStore
+	// Вызывается при помещении токена в карантин
+	OnQuarantine func(token string, err *response.Error)
+
+	mu     sync.Mutex
+	tokens []*tokenState
+	sticky map[string]string
+}
+
+// Состояние токена в пуле
+type tokenState struct {
+	token            string
+	lastUsed         time.Time
+	quarantinedUntil time.Time
+}
+
+// Создает пул токенов
+func New(tokens ...string) *Pool {
+	pool := &Pool{
+		Quarantine: DefaultQuarantine(),
+		sticky:     map[string]string{},
+	}
+
+	for _, token := range tokens {
+		pool.Add(token)
+	}
+
+	return pool
+}
+
+// Добавляет токен в пул
+func (v *Pool) Add(token string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.find(token) == nil {
+		v.tokens = append(v.tokens, &tokenState{token: token})
+	}
+}
+
+// Удаляет токен из пула
+func (v *Pool) Remove(token string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.removeState(token)
+	v.unstick(token)
+}
+
+// Заменяет токен oldToken новым токеном, например полученным после прохождения проверки пользователя.
+// Новый токен занимает место старого, снимается с карантина и закрепляется за ключами старого токена.
+// Если старого токена нет в пуле, новый токен добавляется
+func (v *Pool) Replace(oldToken, newToken string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	state := v.find(oldToken)
+	if state == nil {
+		if v.find(newToken) == nil {
+			v.tokens = append(v.tokens, &tokenState{token: newToken})
+		}
+		return
+	}
+
+	if v.find(newToken) != nil {
+		v.removeState(oldToken)
+		v.unstick(oldToken)
+		return
+	}
+
+	state.token = newToken
+	state.quarantinedUntil = time.Time{}
+	for key, stickyToken := range v.sticky {
+		if stickyToken == oldToken {
+			v.sticky[key] = newToken
+		}
+	}
+}
+
+// Сообщает, находится ли токен в карантине
+func (v *Pool) Quarantined(token string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	state := v.find(token)
+	return state != nil && state.quarantinedUntil.After(time.Now())
+}
+
+// Возвращает количество доступных токенов
+func (v *Pool) Available() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.available(time.Now()))
+}
+
+// Выбирает токен для запроса. Реализует executor.TokenProvider
+func (v *Pool) Token(ctx context.Context, req *request.Request) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	available := v.available(now)
+	if len(available) == 0 {
+		return "", ErrNoTokens
+	}
+
+	stickyKey, _ := ctx.Value(stickyKeyContextKey{}).(string)
+	if stickyKey != "" {
+		if token, ok := v.sticky[stickyKey]; ok {
+			if state := v.find(token); state != nil && !state.quarantinedUntil.After(now) {
+				state.lastUsed = now
+				return token, nil
+			}
+		}
+	}
+
+	var chosen *tokenState
+	chosenReady := false
+	for _, state := range available {
+		ready := v.limiterReady(state.token)
+		if chosen == nil || (ready && !chosenReady) || (ready == chosenReady && state.lastUsed.Before(chosen.lastUsed)) {
+			chosen = state
+			chosenReady = ready
+		}
+	}
+
+	chosen.lastUsed = now
+	if stickyKey != "" {
+		v.sticky[stickyKey] = chosen.token
+	}
+
+	return chosen.token, nil
+}
+
+// Обрабатывает результат запроса с токеном. Реализует executor.TokenProvider.
+// Помещает токен в карантин при ошибках из таблицы Quarantine и возвращает true,
+// если в пуле есть другие доступные токены для повторной отправки
+func (v *Pool) Release(ctx context.Context, token string, err error) bool {
+	var apiError *response.Error
+	if !errors.As(err, &apiError) {
+		return false
+	}
+
+	quarantine, ok := v.Quarantine[apiError.IntCode()]
+	if !ok {
+		return false
+	}
+
+	v.mu.Lock()
+	state := v.find(token)
+	if state == nil {
+		v.mu.Unlock()
+		return false
+	}
+
+	now := time.Now()
+	state.quarantinedUntil = now.Add(quarantine)
+	v.unstick(token)
+	retry := len(v.available(now)) > 0
+	v.mu.Unlock()
+
+	if v.OnQuarantine != nil {
+		v.OnQuarantine(token, apiError)
+	}
+
+	return retry
+}
+
+// Возвращает токены не в карантине
+func (v *Pool) available(now time.Time) []*tokenState {
+	available := make([]*tokenState, 0, len(v.tokens))
+	for _, state := range v.tokens {
+		if !state.quarantinedUntil.After(now) {
+			available = append(available, state)
+		}
+	}
+	return available
+}
+
+// Возвращает состояние токена
+func (v *Pool) find(token string) *tokenState {
+	for _, state := range v.tokens {
+		if state.token == token {
+			return state
+		}
+	}
+	return nil
+}
+
+// Удаляет состояние токена
+func (v *Pool) removeState(token string) {
+	for i, state := range v.tokens {
+		if state.token == token {
+			v.tokens = append(v.tokens[:i], v.tokens[i+1:]...)
+			return
+		}
+	}
+}
+
+// Снимает закрепление токена со всех ключей
+func (v *Pool) unstick(token string) {
+	for key, stickyToken := range v.sticky {
+		if stickyToken == token {
+			delete(v.sticky, key)
+		}
+	}
+}
+
+// Сообщает, пропустит ли лимитер токена запрос без ожидания.
+// Лимитеры, не позволяющие это узнать, считаются готовыми
+func (v *Pool) limiterReady(token string) bool {
+	if v.Limiters == nil {
+		return true
+	}
+
+	req := request.New()
+	req.GetParams().AccessToken(token)
+
+	waitLimiter, err := v.Limiters.GetLimiter(req)
+	if err != nil {
+		return false
+	}
+
+	if tokensLimiter, ok := waitLimiter.(interface{ Tokens() float64 }); ok {
+		return tokensLimiter.Tokens() >= 1
+	}
+
+	return true
+}
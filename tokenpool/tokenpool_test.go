@@ -0,0 +1,180 @@
+package tokenpool_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/limiter"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+	"github.com/ciricc/vkapiexecutor/tokenpool"
+)
+
+// Сервер, возвращающий ошибку авторизации для токена bad_token
+func newTokenServer(used *[]string, mu *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		token := r.PostForm.Get("access_token")
+
+		mu.Lock()
+		*used = append(*used, token)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if token == "bad_token" {
+			w.Write([]byte(`{"error":{"error_code":5,"error_msg":"User authorization failed"}}`))
+			return
+		}
+		w.Write([]byte(`{"response":"` + token + `"}`))
+	}))
+}
+
+func TestPool(t *testing.T) {
+	t.Run("request is resent with another token after auth error", func(t *testing.T) {
+		var used []string
+		var mu sync.Mutex
+		server := newTokenServer(&used, &mu)
+		defer server.Close()
+
+		pool := tokenpool.New("bad_token", "good_token")
+		exec := executor.New()
+		exec.Tokens = pool
+
+		req := request.New()
+		req.Method("users.get")
+		req.BaseUrl(server.URL + "/method/")
+
+		res, err := exec.DoRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != `{"response":"good_token"}` {
+			t.Errorf("unexpected response: %s", res.String())
+		}
+
+		if len(used) != 2 || used[0] != "bad_token" || used[1] != "good_token" {
+			t.Errorf("unexpected tokens order: %v", used)
+		}
+
+		if !pool.Quarantined("bad_token") {
+			t.Errorf("bad_token must be quarantined")
+		}
+
+		if req.GetParams().GetAccessToken() != "" {
+			t.Errorf("template request must not be modified")
+		}
+	})
+
+	t.Run("returns error when all tokens are quarantined", func(t *testing.T) {
+		var used []string
+		var mu sync.Mutex
+		server := newTokenServer(&used, &mu)
+		defer server.Close()
+
+		exec := executor.New()
+		exec.Tokens = tokenpool.New("bad_token")
+
+		req := request.New()
+		req.Method("users.get")
+		req.BaseUrl(server.URL + "/method/")
+
+		_, err := exec.DoRequest(req)
+		if err == nil {
+			t.Fatal("expected auth error")
+		}
+
+		_, err = exec.DoRequest(req)
+		if err != tokenpool.ErrNoTokens {
+			t.Errorf("expected ErrNoTokens, got %v", err)
+		}
+	})
+
+	t.Run("least recently used token is chosen", func(t *testing.T) {
+		pool := tokenpool.New("a", "b", "c")
+		ctx := context.Background()
+
+		var got []string
+		for i := 0; i < 6; i++ {
+			token, err := pool.Token(ctx, request.New())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, token)
+			time.Sleep(time.Millisecond)
+		}
+
+		want := []string{"a", "b", "c", "a", "b", "c"}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("unexpected rotation: %v", got)
+			}
+		}
+	})
+
+	t.Run("sticky key keeps token until quarantine", func(t *testing.T) {
+		pool := tokenpool.New("a", "b")
+		ctx := tokenpool.WithStickyKey(context.Background(), "user")
+
+		first, _ := pool.Token(ctx, request.New())
+		second, _ := pool.Token(ctx, request.New())
+		if first != second {
+			t.Fatalf("sticky token changed: %s -> %s", first, second)
+		}
+
+		retry := pool.Release(ctx, first, response.NewError("Access denied", 15))
+		if !retry {
+			t.Errorf("expected retry with another token")
+		}
+
+		third, _ := pool.Token(ctx, request.New())
+		if third == first {
+			t.Errorf("quarantined token must not be used")
+		}
+	})
+
+	t.Run("replaced token keeps sticky keys and leaves quarantine", func(t *testing.T) {
+		pool := tokenpool.New("a")
+		ctx := tokenpool.WithStickyKey(context.Background(), "user")
+
+		if _, err := pool.Token(ctx, request.New()); err != nil {
+			t.Fatal(err)
+		}
+		pool.Release(ctx, "a", response.NewError("User authorization failed", 5))
+
+		pool.Replace("a", "new")
+		if pool.Quarantined("new") || pool.Available() != 1 {
+			t.Errorf("replaced token must be available")
+		}
+
+		token, err := pool.Token(ctx, request.New())
+		if err != nil || token != "new" {
+			t.Errorf("expected new token, got %q, %v", token, err)
+		}
+	})
+
+	t.Run("token with exhausted limiter is skipped", func(t *testing.T) {
+		store := limiter.NewLimiterStoreTtlCache(1, time.Minute, time.Minute)
+		pool := tokenpool.New("a", "b")
+		pool.Limiters = store
+
+		req := request.New()
+		req.GetParams().AccessToken("a")
+		waitLimiter, _ := store.GetLimiter(req)
+		waitLimiter.Wait(context.Background())
+
+		token, err := pool.Token(context.Background(), request.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token != "b" {
+			t.Errorf("expected token with free limiter, got %s", token)
+		}
+	})
+}
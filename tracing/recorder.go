@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Трассировщик, сохраняющий завершенные span'ы в памяти. Предназначен для тестов и отладки
+//
+//	recorder := tracing.NewRecorder()
+//	exec.Tracer = recorder
+//	...
+//	spans := recorder.Spans()
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// Завершенный span
+type RecordedSpan struct {
+	Name       string
+	Context    SpanContext
+	ParentId   string // Идентификатор родительского span'а, пустой для корневого
+	Attributes []Attribute
+	Errors     []error
+	StartTime  time.Time
+	EndTime    time.Time
+
+	recorder *Recorder
+	mu       sync.Mutex
+	ended    bool
+}
+
+// Создает трассировщик, сохраняющий span'ы в памяти
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Реализует интерфейс Tracer
+func (v *Recorder) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	span := &RecordedSpan{
+		Name:       name,
+		Attributes: append([]Attribute(nil), attrs...),
+		StartTime:  time.Now(),
+		recorder:   v,
+	}
+
+	parent := SpanFromContext(ctx).SpanContext()
+	span.Context.SpanId = randomId(8)
+	if parent.IsValid() {
+		span.Context.TraceId = parent.TraceId
+		span.ParentId = parent.SpanId
+	} else {
+		span.Context.TraceId = randomId(16)
+	}
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Возвращает завершенные span'ы в порядке завершения
+func (v *Recorder) Spans() []*RecordedSpan {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]*RecordedSpan(nil), v.spans...)
+}
+
+// Возвращает завершенные span'ы с указанным именем
+func (v *Recorder) SpansByName(name string) []*RecordedSpan {
+	var spans []*RecordedSpan
+	for _, span := range v.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// Удаляет сохраненные span'ы
+func (v *Recorder) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.spans = nil
+}
+
+// Реализует интерфейс Span
+func (s *RecordedSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes = append(s.Attributes, attrs...)
+}
+
+// Реализует интерфейс Span
+func (s *RecordedSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Errors = append(s.Errors, err)
+}
+
+// Реализует интерфейс Span. Повторные вызовы игнорируются
+func (s *RecordedSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.mu.Unlock()
+
+	s.recorder.mu.Lock()
+	s.recorder.spans = append(s.recorder.spans, s)
+	s.recorder.mu.Unlock()
+}
+
+// Реализует интерфейс Span
+func (s *RecordedSpan) SpanContext() SpanContext {
+	return s.Context
+}
+
+// Возвращает значение атрибута, последнее из добавленных
+func (s *RecordedSpan) Attribute(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.Attributes) - 1; i >= 0; i-- {
+		if s.Attributes[i].Key == key {
+			return s.Attributes[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+func randomId(size int) string {
+	id := make([]byte, size)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,118 @@
+// Пакет tracing описывает трассировку запросов к VK API.
+// Интерфейсы повторяют форму OpenTelemetry, поэтому их легко реализовать поверх него.
+// Трассировщик и текущий span передаются через context.Context, который executor
+// прокидывает в HTTP запрос, хуки и response.Response.Context()
+package tracing
+
+import (
+	"context"
+	"fmt"
+)
+
+// Атрибут span'а
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Создает строковый атрибут
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Создает целочисленный атрибут
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Создает логический атрибут
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func (a Attribute) String() string {
+	return fmt.Sprintf("%s=%v", a.Key, a.Value)
+}
+
+// Идентификаторы span'а в формате W3C Trace Context
+type SpanContext struct {
+	TraceId string // 16 байт в hex
+	SpanId  string // 8 байт в hex
+}
+
+// Сообщает, заданы ли идентификаторы
+func (v SpanContext) IsValid() bool {
+	return v.TraceId != "" && v.SpanId != ""
+}
+
+// Создает span'ы
+type Tracer interface {
+	// Начинает span, дочерний для span'а из ctx, и возвращает контекст с новым span'ом
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Операция в трассировке
+type Span interface {
+	// Добавляет атрибуты
+	SetAttributes(attrs ...Attribute)
+	// Записывает ошибку операции. nil игнорируется
+	RecordError(err error)
+	// Завершает span
+	End()
+	// Возвращает идентификаторы span'а
+	SpanContext() SpanContext
+}
+
+// Ключи трассировщика и span'а в контексте
+type tracerContextKey struct{}
+type spanContextKey struct{}
+
+// Возвращает контекст с трассировщиком, который используют tracing.Start() и executor
+func ContextWithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerContextKey{}, tracer)
+}
+
+// Возвращает трассировщик из контекста или трассировщик, не создающий span'ов
+func TracerFromContext(ctx context.Context) Tracer {
+	if ctx != nil {
+		if tracer, ok := ctx.Value(tracerContextKey{}).(Tracer); ok {
+			return tracer
+		}
+	}
+	return NoopTracer{}
+}
+
+// Возвращает контекст с текущим span'ом. Используется реализациями Tracer
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// Возвращает текущий span из контекста или span, ничего не записывающий
+func SpanFromContext(ctx context.Context) Span {
+	if ctx != nil {
+		if span, ok := ctx.Value(spanContextKey{}).(Span); ok {
+			return span
+		}
+	}
+	return noopSpan{}
+}
+
+// Начинает span трассировщиком из контекста.
+// Если трассировщик не задан, span ничего не записывает
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return TracerFromContext(ctx).Start(ctx, name, attrs...)
+}
+
+// Трассировщик, не создающий span'ов
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+func (noopSpan) SpanContext() SpanContext         { return SpanContext{} }
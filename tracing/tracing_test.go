@@ -0,0 +1,119 @@
+package tracing_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/limiter"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+	"github.com/ciricc/vkapiexecutor/tracing"
+)
+
+// Транспорт, отвечающий ошибкой на первый запрос и успехом на остальные
+type renewRoundTripper struct {
+	calls int
+}
+
+func (v *renewRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v.calls++
+	body := `{"response":1}`
+	if v.calls == 1 {
+		body = `{"error":{"error_code":6,"error_msg":"Too many requests per second"}}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := tracing.NewRecorder()
+
+	tripper := limiter.New(100, time.Minute, time.Minute).(*limiter.Tripper)
+	tripper.Tripper = &renewRoundTripper{}
+
+	exec := executor.New()
+	exec.HttpClient = &http.Client{Transport: tripper}
+	exec.Tracer = recorder
+
+	var responseSpans []tracing.SpanContext
+	exec.ApiResponseHook(func(next executor.ApiResponseNextHook, res response.Response) error {
+		responseSpans = append(responseSpans, tracing.SpanFromContext(res.Context()).SpanContext())
+		if res.Error() != nil {
+			res.Renew(true)
+		}
+		return next(res)
+	})
+
+	req := request.New()
+	req.Method("users.get")
+	req.GetParams().AccessToken("token")
+
+	if _, err := exec.DoRequest(req); err != nil {
+		t.Fatal(err)
+	}
+
+	roots := recorder.SpansByName("vkapi.request")
+	if len(roots) != 1 {
+		t.Fatalf("expected one request span, got %d", len(roots))
+	}
+	root := roots[0]
+
+	if method, _ := root.Attribute("vkapi.method"); method != "users.get" {
+		t.Errorf("unexpected method attribute: %v", method)
+	}
+
+	attempts := recorder.SpansByName("vkapi.attempt")
+	if len(attempts) != 2 {
+		t.Fatalf("expected two attempt spans, got %d", len(attempts))
+	}
+
+	for _, attempt := range attempts {
+		if attempt.ParentId != root.Context.SpanId || attempt.Context.TraceId != root.Context.TraceId {
+			t.Errorf("attempt span must be child of request span")
+		}
+	}
+
+	if reason, _ := attempts[1].Attribute("vkapi.attempt.reason"); reason != "renew" {
+		t.Errorf("unexpected second attempt reason: %v", reason)
+	}
+
+	parents := map[string]string{
+		"vkapi.http":              "vkapi.attempt",
+		"vkapi.limiter.wait":      "vkapi.http",
+		"vkapi.parse":             "vkapi.attempt",
+		"vkapi.hook.api_response": "vkapi.attempt",
+	}
+
+	byId := map[string]*tracing.RecordedSpan{}
+	for _, span := range recorder.Spans() {
+		byId[span.Context.SpanId] = span
+	}
+
+	for name, parentName := range parents {
+		spans := recorder.SpansByName(name)
+		if len(spans) != 2 {
+			t.Errorf("expected two %s spans, got %d", name, len(spans))
+			continue
+		}
+
+		for _, span := range spans {
+			if parent := byId[span.ParentId]; parent == nil || parent.Name != parentName {
+				t.Errorf("span %s must be child of %s", name, parentName)
+			}
+		}
+	}
+
+	for i, spanContext := range responseSpans {
+		if spanContext != attempts[i].Context {
+			t.Errorf("response context must carry attempt span")
+		}
+	}
+}
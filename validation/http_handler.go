@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Обработчик проверки, который открывает redirect_uri по HTTP, проходит по всем перенаправлениям
+// и извлекает результат из адреса последней страницы (параметры access_token, success, fail, error).
+// Подходит для проверок, не требующих действий пользователя,
+// например, когда HTTP клиент уже содержит cookie авторизованной сессии
+type HttpHandler struct {
+	HttpClient *http.Client
+}
+
+// Создает обработчик проверки по HTTP
+func NewHttpHandler(client *http.Client) *HttpHandler {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HttpHandler{
+		HttpClient: client,
+	}
+}
+
+// Реализует интерфейс Handler
+func (v *HttpHandler) Validate(ctx context.Context, redirectUri string) (string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, redirectUri, nil)
+	if err != nil {
+		return "", fmt.Errorf("validation request error: %w", err)
+	}
+
+	res, err := v.HttpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("validation request error: %w", err)
+	}
+	defer res.Body.Close()
+
+	io.Copy(io.Discard, res.Body)
+
+	return parseResultUrl(res.Request.URL)
+}
+
+// Извлекает результат проверки из фрагмента или параметров адреса
+func parseResultUrl(resultUrl *url.URL) (string, error) {
+	values := resultUrl.Query()
+	if fragment, err := url.ParseQuery(resultUrl.Fragment); err == nil {
+		for key, val := range fragment {
+			values[key] = val
+		}
+	}
+
+	if values.Has("fail") || values.Has("error") {
+		return "", fmt.Errorf("%w: %s", ErrValidationFailed, values.Get("error_description"))
+	}
+
+	if token := values.Get("access_token"); token != "" {
+		return token, nil
+	}
+
+	if values.Get("success") == "1" {
+		return "", nil
+	}
+
+	return "", fmt.Errorf("%w: unexpected result url %s", ErrValidationFailed, resultUrl.Redacted())
+}
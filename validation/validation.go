@@ -0,0 +1,98 @@
+// Пакет validation реализует обработку ошибки VK API 17 (Validation required)
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Код ошибки VK API "Validation required"
+const ErrorCodeValidationRequired = int(response.ErrorCodeValidationRequired)
+
+// Возвращается, если проверка не пройдена
+var ErrValidationFailed = errors.New("validation failed")
+
+// Обработчик проверки пользователя
+type Handler interface {
+	// Проходит проверку по адресу redirectUri из ошибки VK API.
+	// Возвращает новый токен доступа или пустую строку, если проверка подтверждена и запрос нужно повторить со старым токеном
+	Validate(ctx context.Context, redirectUri string) (string, error)
+}
+
+// Позволяет использовать функцию как обработчик проверки
+type HandlerFunc func(ctx context.Context, redirectUri string) (string, error)
+
+func (f HandlerFunc) Validate(ctx context.Context, redirectUri string) (string, error) {
+	return f(ctx, redirectUri)
+}
+
+// Хук API ответа, обрабатывающий ошибку 17.
+// Передает redirect_uri обработчику, ждет новый токен или подтверждение,
+// заменяет access_token в запросе и переотправляет его.
+// Токен заменяется в копии запроса, которую executor создает для каждого вызова, поэтому общий шаблон запроса не изменяется.
+// Если у executor'а задан источник токенов executor.Tokens, он подставляет свой токен в каждую попытку,
+// поэтому новый токен нужно передать источнику через OnToken:
+//
+//	pool := tokenpool.New(tokens...)
+//	hook := validation.New(handler)
+//	hook.OnToken = pool.Replace
+type Hook struct {
+	Handler Handler       // Обработчик проверки
+	Timeout time.Duration // Максимальное время ожидания обработчика, 0 - без ограничения
+	// Вызывается перед переотправкой запроса с новым токеном, полученным от обработчика
+	OnToken func(oldToken, newToken string)
+}
+
+// Создает хук проверки
+//
+//	exec.ApiResponseHook(validation.New(handler).ApiResponseHook)
+func New(handler Handler) *Hook {
+	return &Hook{
+		Handler: handler,
+		Timeout: 5 * time.Minute,
+	}
+}
+
+// Реализует executor.ApiResponseHook
+func (v *Hook) ApiResponseHook(next executor.ApiResponseNextHook, res response.Response) error {
+	ctx := res.Context()
+	req := executor.GetRequest(ctx)
+
+	var apiError *response.Error
+	if !errors.As(res.Error(), &apiError) || apiError.IntCode() != ErrorCodeValidationRequired || apiError.RedirectUri == "" || req == nil {
+		return next(res)
+	}
+
+	if v.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.Timeout)
+		defer cancel()
+	}
+
+	token, err := v.Handler.Validate(ctx, apiError.RedirectUri)
+	if err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	if token != "" {
+		if v.OnToken != nil {
+			v.OnToken(req.GetToken(), token)
+		}
+
+		params := req.GetParams()
+		if params == nil {
+			params = request.NewParams()
+			req.Params(params)
+		}
+		params.AccessToken(token)
+	}
+
+	res.Renew(true)
+	return next(res)
+}
@@ -0,0 +1,149 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/tokenpool"
+	"github.com/ciricc/vkapiexecutor/validation"
+)
+
+// Сервер, требующий проверку для всех токенов, кроме валидного
+func newValidationServer(location string) *httptest.Server {
+	server := httptest.NewServer(nil)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/validate":
+			http.Redirect(w, r, location, http.StatusFound)
+		case "/blank.html":
+			w.Write([]byte("ok"))
+		default:
+			r.ParseForm()
+			w.Header().Set("Content-Type", "application/json")
+			if r.PostForm.Get("access_token") == "valid_token" {
+				w.Write([]byte(`{"response":1}`))
+				return
+			}
+			w.Write([]byte(`{"error":{"error_code":17,"error_msg":"Validation required",` +
+				`"redirect_uri":"` + server.URL + `/validate?sid=1"}}`))
+		}
+	})
+	return server
+}
+
+func TestHook(t *testing.T) {
+	newRequest := func(server *httptest.Server) *request.Request {
+		req := request.New()
+		req.Method("account.getInfo")
+		req.BaseUrl(server.URL + "/method/")
+		req.GetParams().AccessToken("old_token")
+		return req
+	}
+
+	t.Run("http handler follows redirect and updates token", func(t *testing.T) {
+		server := newValidationServer("/blank.html#access_token=valid_token&user_id=1")
+		defer server.Close()
+
+		exec := executor.New()
+		exec.ApiResponseHook(validation.New(validation.NewHttpHandler(nil)).ApiResponseHook)
+
+		req := newRequest(server)
+		res, err := exec.DoRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != `{"response":1}` {
+			t.Errorf("unexpected response: %s", res)
+		}
+
+		if token := executor.GetRequest(res.Context()).GetToken(); token != "valid_token" {
+			t.Errorf("access token not updated: %q", token)
+		}
+	})
+
+	t.Run("new token replaces pool token", func(t *testing.T) {
+		server := newValidationServer("/blank.html")
+		defer server.Close()
+
+		pool := tokenpool.New("old_token")
+		hook := validation.New(validation.HandlerFunc(func(ctx context.Context, redirectUri string) (string, error) {
+			return "valid_token", nil
+		}))
+		hook.OnToken = pool.Replace
+
+		exec := executor.New()
+		exec.Tokens = pool
+		exec.ApiResponseHook(hook.ApiResponseHook)
+
+		template := newRequest(server)
+		if _, err := exec.DoRequest(template); err != nil {
+			t.Fatal(err)
+		}
+
+		if token, _ := pool.Token(context.Background(), template); token != "valid_token" {
+			t.Errorf("pool token not replaced: %q", token)
+		}
+
+		if template.GetParams().GetAccessToken() != "old_token" {
+			t.Errorf("shared request changed: %q", template.GetParams().GetAccessToken())
+		}
+	})
+
+	t.Run("http handler validation failed", func(t *testing.T) {
+		server := newValidationServer("/blank.html#fail=1")
+		defer server.Close()
+
+		exec := executor.New()
+		exec.ApiResponseHook(validation.New(validation.NewHttpHandler(nil)).ApiResponseHook)
+
+		if _, err := exec.DoRequest(newRequest(server)); !errors.Is(err, validation.ErrValidationFailed) {
+			t.Errorf("expected validation failed error, got %v", err)
+		}
+	})
+
+	t.Run("handler waits with context", func(t *testing.T) {
+		server := newValidationServer("/blank.html")
+		defer server.Close()
+
+		hook := validation.New(validation.HandlerFunc(func(ctx context.Context, redirectUri string) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}))
+
+		exec := executor.New()
+		exec.ApiResponseHook(hook.ApiResponseHook)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := exec.DoRequestCtx(ctx, newRequest(server)); err == nil {
+			t.Errorf("expected context error")
+		}
+	})
+
+	t.Run("custom handler receives redirect uri", func(t *testing.T) {
+		server := newValidationServer("/blank.html")
+		defer server.Close()
+
+		var gotUri string
+		exec := executor.New()
+		exec.ApiResponseHook(validation.New(validation.HandlerFunc(func(ctx context.Context, redirectUri string) (string, error) {
+			gotUri = redirectUri
+			return "valid_token", nil
+		})).ApiResponseHook)
+
+		if _, err := exec.DoRequest(newRequest(server)); err != nil {
+			t.Fatal(err)
+		}
+
+		if gotUri != server.URL+"/validate?sid=1" {
+			t.Errorf("unexpected redirect uri: %q", gotUri)
+		}
+	})
+}
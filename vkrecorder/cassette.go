@@ -0,0 +1,74 @@
+package vkrecorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Кассета - записанные пары запросов и ответов
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Записанная пара запроса и ответа
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Записанный запрос к VK API. Значения секретных параметров заменены на request.RedactedValue
+type RecordedRequest struct {
+	Method string     `json:"method"`
+	Url    string     `json:"url"`
+	Params url.Values `json:"params"`
+}
+
+// Записанный HTTP ответ
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Загружает кассету из файла
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette error: %w", err)
+	}
+
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("decode cassette error: %w", err)
+	}
+
+	return cassette, nil
+}
+
+// Сохраняет кассету в файл, создавая директорию при необходимости
+func (v *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette error: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cassette dir error: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write cassette error: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write cassette error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+// Пакет vkrecorder реализует транспорт, записывающий запросы к VK API в кассеты и воспроизводящий их,
+// чтобы тесты работали без доступа к api.vk.com.
+// Токены и другие секретные параметры не попадают в кассету
+//
+//	rec, err := vkrecorder.New("testdata/users_get.json", vkrecorder.ModeFromEnv(vkrecorder.ModeReplay))
+//	exec.HttpClient = &http.Client{Transport: rec}
+package vkrecorder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+)
+
+// Режим работы транспорта
+type Mode int
+
+const (
+	ModeReplay      Mode = iota // Ответы берутся из кассеты, запросы к серверу не отправляются
+	ModeRecord                  // Запросы отправляются на сервер, ответы записываются в кассету
+	ModePassthrough             // Запросы отправляются на сервер без записи
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	case ModePassthrough:
+		return "passthrough"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// Переменная окружения с режимом работы
+const ModeEnv = "VKRECORDER_MODE"
+
+// Возвращает режим из переменной окружения VKRECORDER_MODE (replay, record или passthrough).
+// Если переменная не задана или содержит неизвестное значение, возвращает fallback
+func ModeFromEnv(fallback Mode) Mode {
+	switch os.Getenv(ModeEnv) {
+	case "replay":
+		return ModeReplay
+	case "record":
+		return ModeRecord
+	case "passthrough":
+		return ModePassthrough
+	}
+	return fallback
+}
+
+// Возвращается в режиме воспроизведения, если в кассете нет подходящего запроса.
+// Оборачивает executor.ErrNotRetryable, поэтому executor не повторяет такой запрос
+var ErrInteractionNotFound = fmt.Errorf("cassette interaction not found: %w", executor.ErrNotRetryable)
+
+// Транспорт записи и воспроизведения запросов.
+// Запросы сопоставляются по методу API и параметрам без учета их порядка и значений секретных параметров.
+// Одинаковые запросы воспроизводятся в порядке записи, после чего повторяется последний ответ
+type Recorder struct {
+	// Режим работы
+	Mode Mode
+	// Транспорт, через который запросы отправляются на сервер в режимах записи и без записи
+	Tripper http.RoundTripper
+	// Параметры, которые не учитываются при сопоставлении запросов, например, случайные идентификаторы
+	IgnoreParams []string
+	// Вызывается перед записью запроса в кассету, например, чтобы скрыть персональные данные в теле ответа
+	Scrub func(interaction *Interaction)
+
+	path string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     map[*Interaction]bool
+}
+
+// Создает транспорт с кассетой в файле path.
+// В режиме воспроизведения кассета загружается из файла, в режиме записи создается новая
+func New(path string, mode Mode) (*Recorder, error) {
+	recorder := &Recorder{
+		Mode:     mode,
+		Tripper:  http.DefaultTransport,
+		path:     path,
+		cassette: &Cassette{},
+		used:     map[*Interaction]bool{},
+	}
+
+	if mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		recorder.cassette = cassette
+	}
+
+	return recorder, nil
+}
+
+// Возвращает кассету
+func (v *Recorder) Cassette() *Cassette {
+	return v.cassette
+}
+
+// Сохраняет кассету в файл. В режиме записи кассета сохраняется после каждого запроса
+func (v *Recorder) Save() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.cassette.Save(v.path)
+}
+
+// Реализует http.RoundTripper
+func (v *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch v.Mode {
+	case ModePassthrough:
+		return v.Tripper.RoundTrip(req)
+	case ModeRecord:
+		return v.record(req)
+	default:
+		return v.replay(req)
+	}
+}
+
+// Отправляет запрос на сервер и записывает ответ
+func (v *Recorder) record(req *http.Request) (*http.Response, error) {
+	recorded, sendReq, err := v.recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := v.Tripper.RoundTrip(sendReq)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	header := res.Header.Clone()
+	header.Del("Set-Cookie")
+
+	interaction := &Interaction{
+		Request: *recorded,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     header,
+			Body:       string(body),
+		},
+	}
+
+	if v.Scrub != nil {
+		v.Scrub(interaction)
+	}
+
+	v.mu.Lock()
+	v.cassette.Interactions = append(v.cassette.Interactions, interaction)
+	err = v.cassette.Save(v.path)
+	v.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	return res, nil
+}
+
+// Возвращает записанный ответ на запрос
+func (v *Recorder) replay(req *http.Request) (*http.Response, error) {
+	recorded, _, err := v.recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := v.matchKey(recorded)
+
+	v.mu.Lock()
+	var found *Interaction
+	for _, interaction := range v.cassette.Interactions {
+		if v.matchKey(&interaction.Request) != key {
+			continue
+		}
+
+		found = interaction
+		if !v.used[interaction] {
+			break
+		}
+	}
+
+	if found != nil {
+		v.used[found] = true
+	}
+	v.mu.Unlock()
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s?%s", ErrInteractionNotFound, recorded.Method, recorded.Params.Encode())
+	}
+
+	header := found.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", found.Response.StatusCode, http.StatusText(found.Response.StatusCode)),
+		StatusCode:    found.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(found.Response.Body)),
+		ContentLength: int64(len(found.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Собирает запрос для кассеты, скрывая секретные параметры.
+// Возвращает также запрос для отправки на сервер, исходный запрос не изменяется
+func (v *Recorder) recordRequest(req *http.Request) (*RecordedRequest, *http.Request, error) {
+	params := url.Values{}
+	for key, values := range req.URL.Query() {
+		params[key] = append(params[key], values...)
+	}
+
+	body, sendReq, err := readRequestBody(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read request body error: %w", err)
+	}
+
+	if body != nil {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse request body error: %w", err)
+		}
+		for key, values := range form {
+			params[key] = append(params[key], values...)
+		}
+	}
+
+	method := path.Base(req.URL.Path)
+	if apiReq := executor.GetRequest(req.Context()); apiReq != nil {
+		method = apiReq.GetMethod()
+	}
+
+	recordedUrl := *req.URL
+	recordedUrl.RawQuery = ""
+
+	return &RecordedRequest{
+		Method: method,
+		Url:    recordedUrl.String(),
+		Params: request.NewParamsFromUrl(params).Redacted(),
+	}, sendReq, nil
+}
+
+// Читает тело запроса, не изменяя сам запрос. Если тело можно получить заново через GetBody,
+// отправляется исходный запрос, иначе его копия с прочитанным телом
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	if req.GetBody != nil {
+		reqBody, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer reqBody.Close()
+
+		body, err := io.ReadAll(reqBody)
+		if err != nil {
+			return nil, nil, err
+		}
+		return body, req, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sendReq := req.Clone(req.Context())
+	sendReq.Body = io.NopCloser(bytes.NewReader(body))
+	sendReq.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, sendReq, nil
+}
+
+// Возвращает ключ сопоставления запроса: метод и отсортированные параметры без игнорируемых
+func (v *Recorder) matchKey(recorded *RecordedRequest) string {
+	params := url.Values{}
+	for key, values := range recorded.Params {
+		params[key] = values
+	}
+
+	for _, key := range v.IgnoreParams {
+		params.Del(key)
+	}
+
+	return recorded.Method + "?" + params.Encode()
+}
+
+// Сообщает, что ошибка вызвана отсутствием запроса в кассете
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrInteractionNotFound)
+}
@@ -0,0 +1,198 @@
+package vkrecorder_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/vkrecorder"
+)
+
+// Транспорт, не допускающий запросов к серверу
+type offlineRoundTripper struct{}
+
+func (offlineRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("network is not available in replay mode")
+}
+
+// Транспорт, запоминающий тело последнего запроса
+type bodyRoundTripper struct {
+	body string
+}
+
+func (v *bodyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	v.body = string(body)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"response":[]}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRecorder(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "remixsid=secret")
+		if calls == 1 {
+			w.Write([]byte(`{"error":{"error_code":6,"error_msg":"Too many requests per second"}}`))
+			return
+		}
+		w.Write([]byte(`{"response":[{"id":` + r.PostForm.Get("user_ids") + `}]}`))
+	}))
+	defer server.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "cassettes", "users_get.json")
+
+	newRequest := func(token string, userIds string) *request.Request {
+		req := request.New()
+		req.Method("users.get")
+		req.BaseUrl(server.URL + "/method/")
+		req.GetParams().AccessToken(token)
+		req.GetParams().Set("user_ids", userIds)
+		return req
+	}
+
+	newExecutor := func(rec *vkrecorder.Recorder) *executor.Executor {
+		exec := executor.New()
+		exec.HttpClient = &http.Client{Transport: rec}
+		exec.RetryPolicy = executor.NewBackoffRetryPolicy(3)
+		return exec
+	}
+
+	t.Run("record scrubs tokens", func(t *testing.T) {
+		rec, err := vkrecorder.New(cassettePath, vkrecorder.ModeRecord)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exec := newExecutor(rec)
+		exec.DoRequest(newRequest("secret_token", "1"))
+		exec.DoRequest(newRequest("secret_token", "1"))
+		exec.DoRequest(newRequest("secret_token", "2"))
+
+		data, err := os.ReadFile(cassettePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(string(data), "secret") {
+			t.Errorf("secret leaked into cassette:\n%s", data)
+		}
+
+		if len(rec.Cassette().Interactions) != 3 {
+			t.Errorf("expected 3 interactions, got %d", len(rec.Cassette().Interactions))
+		}
+	})
+
+	t.Run("replay matches method and params", func(t *testing.T) {
+		rec, err := vkrecorder.New(cassettePath, vkrecorder.ModeReplay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.Tripper = offlineRoundTripper{}
+
+		exec := newExecutor(rec)
+
+		_, err = exec.DoRequest(newRequest("other_token", "1"))
+		if err == nil || !strings.Contains(err.Error(), "Too many requests") {
+			t.Errorf("expected recorded api error, got %v", err)
+		}
+
+		expected := map[string]string{
+			"1": `{"response":[{"id":1}]}`,
+			"2": `{"response":[{"id":2}]}`,
+		}
+		for _, userIds := range []string{"2", "1", "1"} {
+			res, err := exec.DoRequest(newRequest("other_token", userIds))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.String() != expected[userIds] {
+				t.Errorf("unexpected replayed response: %s", res.String())
+			}
+		}
+
+		_, err = exec.DoRequest(newRequest("other_token", "3"))
+		if !vkrecorder.IsNotFound(err) {
+			t.Errorf("expected ErrInteractionNotFound, got %v", err)
+		}
+
+		if calls != 3 {
+			t.Errorf("replay must not send requests, server calls: %d", calls)
+		}
+	})
+
+	t.Run("passthrough does not record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "passthrough.json")
+		rec, err := vkrecorder.New(path, vkrecorder.ModePassthrough)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := newExecutor(rec).DoRequest(newRequest("secret_token", "4")); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("cassette must not be written in passthrough mode")
+		}
+	})
+
+	t.Run("record does not modify request", func(t *testing.T) {
+		rt := &bodyRoundTripper{}
+		rec, err := vkrecorder.New(filepath.Join(t.TempDir(), "body.json"), vkrecorder.ModeRecord)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.Tripper = rt
+
+		for _, withGetBody := range []bool{true, false} {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/method/users.get", strings.NewReader("user_ids=5"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !withGetBody {
+				req.Body = io.NopCloser(strings.NewReader("user_ids=5"))
+				req.GetBody = nil
+			}
+			body := req.Body
+
+			res, err := rec.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if req.Body != body {
+				t.Errorf("request body must not be replaced (GetBody %v)", withGetBody)
+			}
+
+			if rt.body != "user_ids=5" {
+				t.Errorf("unexpected sent body %q (GetBody %v)", rt.body, withGetBody)
+			}
+		}
+
+		for _, interaction := range rec.Cassette().Interactions {
+			if interaction.Request.Params.Get("user_ids") != "5" {
+				t.Errorf("unexpected recorded params: %v", interaction.Request.Params)
+			}
+		}
+	})
+}
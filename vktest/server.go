@@ -0,0 +1,185 @@
+// Пакет vktest реализует тестовый сервер, отвечающий в формате VK API.
+// Ответы задаются для каждого метода заранее, а полученные запросы сохраняются для проверок
+//
+//	srv := vktest.NewServer()
+//	defer srv.Close()
+//
+//	srv.On("users.get").Return(`[{"id":1}]`)
+//	srv.OnError("wall.post", 14, map[string]any{"captcha_sid": "1", "captcha_img": srv.URL + "/captcha.jpg"})
+//
+//	exec := srv.NewExecutor()
+package vktest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Полученный сервером запрос
+type Call struct {
+	Method string      // Метод API
+	Params url.Values  // Параметры из URL и тела запроса
+	Token  string      // Токен доступа или анонимный токен
+	Header http.Header // Заголовки запроса
+}
+
+// Тестовый сервер VK API
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	stubs map[string]*Stub
+	calls []Call
+}
+
+// Запускает тестовый сервер
+func NewServer() *Server {
+	s := &Server{stubs: map[string]*Stub{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Возвращает URL путь к API сервера для request.Request.BaseUrl() и executor.NewHostPool()
+func (s *Server) BaseUrl() string {
+	return s.URL + "/method/"
+}
+
+// Направляет запросы executor'а на сервер через пул хостов
+func (s *Server) Attach(exec *executor.Executor) {
+	hosts, err := executor.NewHostPool(s.BaseUrl())
+	if err != nil {
+		panic(err)
+	}
+	exec.Hosts = hosts
+}
+
+// Создает executor, отправляющий запросы на сервер
+func (s *Server) NewExecutor() *executor.Executor {
+	exec := executor.New()
+	s.Attach(exec)
+	return exec
+}
+
+// Возвращает заглушку метода, создавая ее при необходимости
+func (s *Server) On(method string) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stub, ok := s.stubs[method]
+	if !ok {
+		stub = &Stub{method: method}
+		s.stubs[method] = stub
+	}
+	return stub
+}
+
+// Добавляет в очередь метода ответ с ошибкой VK API.
+// fields - дополнительные поля объекта ошибки, например captcha_sid и captcha_img
+func (s *Server) OnError(method string, code int, fields map[string]any) *Stub {
+	return s.On(method).ReturnError(code, "", fields)
+}
+
+// Возвращает заглушку метода execute
+func (s *Server) OnExecute() *Stub {
+	return s.On(executor.ExecuteMethod)
+}
+
+// Возвращает полученные запросы в порядке получения
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// Возвращает полученные запросы к методу
+func (s *Server) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range s.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Удаляет сохраненные запросы и заглушки
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+	s.stubs = map[string]*Stub{}
+}
+
+// Проверяет, что сервер получил запросы к указанным методам в указанном порядке
+func (s *Server) AssertCalls(t testing.TB, methods ...string) {
+	t.Helper()
+
+	calls := s.Calls()
+	received := make([]string, len(calls))
+	for i, call := range calls {
+		received[i] = call.Method
+	}
+
+	if strings.Join(received, ",") != strings.Join(methods, ",") {
+		t.Errorf("unexpected calls:\nexpected: %v\nreceived: %v", methods, received)
+	}
+}
+
+// Проверяет количество запросов к методу
+func (s *Server) AssertCallCount(t testing.TB, method string, count int) {
+	t.Helper()
+
+	if calls := len(s.CallsTo(method)); calls != count {
+		t.Errorf("unexpected %s calls count: expected %d, received %d", method, count, calls)
+	}
+}
+
+// Проверяет, что все запросы к методу отправлены с токеном
+func (s *Server) AssertToken(t testing.TB, method, token string) {
+	t.Helper()
+
+	for i, call := range s.CallsTo(method) {
+		if call.Token != token {
+			t.Errorf("unexpected token in %s call %d: %q", method, i, call.Token)
+		}
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	call := Call{
+		Method: path.Base(r.URL.Path),
+		Params: r.Form,
+		Token:  r.Form.Get(request.AccessTokenParamKey),
+		Header: r.Header.Clone(),
+	}
+	if call.Token == "" {
+		call.Token = r.Form.Get(request.AnonymousTokenKey)
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	stub := s.stubs[call.Method]
+	s.mu.Unlock()
+
+	if stub == nil {
+		writeReply(w, errorReply(int(response.ErrorCodeUnknownMethod), fmt.Sprintf("Unknown method passed: %s", call.Method), nil))
+		return
+	}
+
+	writeReply(w, stub.next(call))
+}
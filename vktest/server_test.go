@@ -0,0 +1,142 @@
+package vktest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ciricc/vkapiexecutor/captcha"
+	"github.com/ciricc/vkapiexecutor/executor"
+	"github.com/ciricc/vkapiexecutor/request"
+	"github.com/ciricc/vkapiexecutor/vktest"
+)
+
+func TestServer(t *testing.T) {
+	newRequest := func(method string) *request.Request {
+		req := request.New()
+		req.Method(method)
+		req.GetParams().AccessToken("token")
+		return req
+	}
+
+	t.Run("returns stubbed responses and records calls", func(t *testing.T) {
+		srv := vktest.NewServer()
+		defer srv.Close()
+
+		srv.On("users.get").Return(`[{"id":1}]`)
+
+		exec := srv.NewExecutor()
+		req := newRequest("users.get")
+		req.GetParams().Set("user_ids", "1")
+
+		res, err := exec.DoRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != `{"response":[{"id":1}]}` {
+			t.Errorf("unexpected response: %s", res.String())
+		}
+
+		if _, err := exec.DoRequest(newRequest("users.search")); err == nil {
+			t.Errorf("expected unknown method error")
+		}
+
+		srv.AssertCalls(t, "users.get", "users.search")
+		srv.AssertToken(t, "users.get", "token")
+
+		if srv.CallsTo("users.get")[0].Params.Get("user_ids") != "1" {
+			t.Errorf("params not recorded")
+		}
+	})
+
+	t.Run("captcha hook runs offline", func(t *testing.T) {
+		srv := vktest.NewServer()
+		defer srv.Close()
+
+		srv.OnError("wall.post", 14, map[string]any{
+			"captcha_sid": "123",
+			"captcha_img": srv.URL + "/captcha.jpg",
+		})
+		srv.On("wall.post").Return(`{"post_id":1}`)
+
+		exec := srv.NewExecutor()
+		exec.ApiResponseHook(captcha.New(captcha.SolverFunc(func(ctx context.Context, image []byte) (string, error) {
+			return "key", nil
+		})).ApiResponseHook)
+
+		if _, err := exec.DoRequest(newRequest("wall.post")); err != nil {
+			t.Fatal(err)
+		}
+
+		calls := srv.CallsTo("wall.post")
+		if len(calls) != 2 || calls[1].Params.Get("captcha_sid") != "123" || calls[1].Params.Get("captcha_key") != "key" {
+			t.Errorf("unexpected calls: %v", calls)
+		}
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		srv := vktest.NewServer()
+		defer srv.Close()
+
+		srv.On("users.get").ReturnHttpError(http.StatusBadGateway).Return(`[]`)
+
+		exec := srv.NewExecutor()
+		policy := executor.NewBackoffRetryPolicy(3)
+		policy.BaseDelay = time.Millisecond
+		exec.RetryPolicy = policy
+
+		if _, err := exec.DoRequest(newRequest("users.get")); err != nil {
+			t.Fatal(err)
+		}
+
+		srv.AssertCallCount(t, "users.get", 2)
+	})
+
+	t.Run("execute results with errors", func(t *testing.T) {
+		srv := vktest.NewServer()
+		defer srv.Close()
+
+		srv.OnExecute().ReturnExecute(
+			[]any{[]any{map[string]any{"id": 1}}, false},
+			vktest.ExecuteError{Method: "wall.get", Code: 15, Msg: "Access denied"},
+		)
+
+		exec := srv.NewExecutor()
+		responses, err := exec.DoBatch(context.Background(), []*request.Request{
+			newRequest("users.get"),
+			newRequest("wall.get"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if responses[0].Error() != nil || responses[1].Error() == nil {
+			t.Errorf("unexpected batch errors: %v, %v", responses[0].Error(), responses[1].Error())
+		}
+
+		srv.AssertCalls(t, "execute")
+	})
+
+	t.Run("handler computes response from call", func(t *testing.T) {
+		srv := vktest.NewServer()
+		defer srv.Close()
+
+		srv.On("utils.resolveScreenName").Handle(func(call vktest.Call) any {
+			return map[string]any{"screen_name": call.Params.Get("screen_name")}
+		})
+
+		req := newRequest("utils.resolveScreenName")
+		req.GetParams().Set("screen_name", "durov")
+
+		res, err := srv.NewExecutor().DoRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != `{"response":{"screen_name":"durov"}}` {
+			t.Errorf("unexpected response: %s", res.String())
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package vktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ciricc/vkapiexecutor/response"
+)
+
+// Заглушка метода с очередью ответов.
+// Ответы возвращаются по порядку, последний ответ повторяется для всех следующих запросов
+type Stub struct {
+	method string
+
+	mu      sync.Mutex
+	replies []reply
+}
+
+// Ответ сервера
+type reply struct {
+	status      int
+	contentType string
+	body        []byte
+	handler     func(call Call) any
+}
+
+// Ошибка выполнения метода внутри execute
+type ExecuteError struct {
+	Method string `json:"method"`
+	Code   int    `json:"error_code"`
+	Msg    string `json:"error_msg"`
+}
+
+// Добавляет в очередь успешный ответ. value - JSON значение поля response
+func (v *Stub) Return(value string) *Stub {
+	return v.ReturnRaw(`{"response":` + value + `}`)
+}
+
+// Добавляет в очередь успешный ответ со значением поля response, сериализованным в JSON
+func (v *Stub) ReturnValue(value any) *Stub {
+	return v.push(reply{status: http.StatusOK, contentType: "application/json", body: mustJson(map[string]any{"response": value})})
+}
+
+// Добавляет в очередь ответ с произвольным телом
+func (v *Stub) ReturnRaw(body string) *Stub {
+	return v.push(reply{status: http.StatusOK, contentType: "application/json", body: []byte(body)})
+}
+
+// Добавляет в очередь ответ с ошибкой VK API. Если msg пустой, используется описание кода из response.ErrorCode.
+// fields - дополнительные поля объекта ошибки
+func (v *Stub) ReturnError(code int, msg string, fields map[string]any) *Stub {
+	if msg == "" {
+		msg = response.ErrorCode(code).DescriptionEn()
+	}
+	if msg == "" {
+		msg = fmt.Sprintf("Error %d", code)
+	}
+	return v.push(errorReply(code, msg, fields))
+}
+
+// Добавляет в очередь ответ execute с результатами вызовов и ошибками execute_errors.
+// Результаты вызовов, завершившихся ошибкой, должны быть равны false
+func (v *Stub) ReturnExecute(results []any, executeErrors ...ExecuteError) *Stub {
+	body := map[string]any{"response": results}
+	if len(executeErrors) > 0 {
+		body["execute_errors"] = executeErrors
+	}
+	return v.push(reply{status: http.StatusOK, contentType: "application/json", body: mustJson(body)})
+}
+
+// Добавляет в очередь HTTP ответ с кодом status и HTML телом, как у страницы ошибки балансировщика
+func (v *Stub) ReturnHttpError(status int) *Stub {
+	return v.push(reply{
+		status:      status,
+		contentType: "text/html",
+		body:        []byte("<html><body>" + http.StatusText(status) + "</body></html>"),
+	})
+}
+
+// Добавляет в очередь ответ, вычисляемый по запросу. Значение, возвращенное handler, сериализуется в поле response
+func (v *Stub) Handle(handler func(call Call) any) *Stub {
+	return v.push(reply{handler: handler})
+}
+
+func (v *Stub) push(r reply) *Stub {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.replies = append(v.replies, r)
+	return v
+}
+
+// Возвращает следующий ответ очереди
+func (v *Stub) next(call Call) reply {
+	v.mu.Lock()
+	var r reply
+	switch len(v.replies) {
+	case 0:
+		r = errorReply(int(response.ErrorCodeInternalServer), "Internal server error: no replies for "+v.method, nil)
+	case 1:
+		r = v.replies[0]
+	default:
+		r = v.replies[0]
+		v.replies = v.replies[1:]
+	}
+	v.mu.Unlock()
+
+	if r.handler != nil {
+		return reply{status: http.StatusOK, contentType: "application/json", body: mustJson(map[string]any{"response": r.handler(call)})}
+	}
+
+	return r
+}
+
+func errorReply(code int, msg string, fields map[string]any) reply {
+	errorObject := map[string]any{}
+	for key, value := range fields {
+		errorObject[key] = value
+	}
+	errorObject["error_code"] = code
+	errorObject["error_msg"] = msg
+
+	return reply{
+		status:      http.StatusOK,
+		contentType: "application/json",
+		body:        mustJson(map[string]any{"error": errorObject}),
+	}
+}
+
+func writeReply(w http.ResponseWriter, r reply) {
+	w.Header().Set("Content-Type", r.contentType)
+	w.WriteHeader(r.status)
+	w.Write(r.body)
+}
+
+func mustJson(value any) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Sprintf("vktest: encode reply error: %s", err))
+	}
+	return data
+}